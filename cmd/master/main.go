@@ -13,7 +13,10 @@ import (
 
 	http_api "distributed-cron/internal/api/http"
 	"distributed-cron/internal/config"
+	"distributed-cron/internal/domain"
+	"distributed-cron/internal/infra/archive"
 	"distributed-cron/internal/infra/etcd"
+	"distributed-cron/internal/infra/sqlite"
 	"distributed-cron/internal/master"
 	"distributed-cron/internal/scheduler"
 	"distributed-cron/internal/tracing"
@@ -30,6 +33,7 @@ func corsMiddleware(next http.Handler) http.Handler {
 		w.Header().Set("Access-Control-Allow-Origin", "*") // For local dev, allow all origins
 		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
 		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Next-Cursor")
 
 		// Handle pre-flight requests
 		if r.Method == "OPTIONS" {
@@ -85,23 +89,79 @@ func main() {
 
 	// 6. Instantiate components
 	discovery := master.NewWorkerDiscovery(etcdClient, logger)
-	dispatcher := master.NewDispatcher(discovery, logger)
-	jobRepo := etcd.NewEtcdJobRepository(etcdClient, logger)
-	execRepo := etcd.NewEtcdExecutionRepository(etcdClient, logger)
+	strategy, err := master.NewSelectionStrategy(cfg.Dispatch.Strategy)
+	if err != nil {
+		log.Fatalf("Invalid dispatch configuration: %v", err)
+	}
+	healthChecker := master.NewHealthChecker(discovery, cfg.Health.ProbeInterval, cfg.Health.ProbeTimeout, cfg.Health.FailureThreshold, cfg.Health.Cooldown, logger)
+	dispatcher := master.NewDispatcher(discovery, strategy, healthChecker, cfg.Dispatch.Timeout, logger)
+	var (
+		jobRepo     domain.JobRepository
+		jobWatcher  domain.JobWatcher
+		execRepo    domain.ExecutionRepository
+		leaderTasks []usecase.LeaderTask
+		storageID   string // Published with the candidacy so workers can check they share the database
+	)
+	clusterObserver := etcd.NewEtcdClusterObserver(etcdClient)
+	switch cfg.Storage.Backend {
+	case config.StorageBackendEtcd:
+		jobRepo = etcd.NewEtcdJobRepository(etcdClient, logger)
+		jobWatcher = etcd.NewEtcdJobWatcher(etcdClient, logger)
+		execRepo = etcd.NewEtcdExecutionRepository(etcdClient, logger)
+		leaderTasks = append(leaderTasks, etcd.NewEtcdHistoryMigrator(etcdClient, logger))
+	case config.StorageBackendSQLite:
+		db, err := sqlite.Open(rootCtx, cfg.Storage.SQLitePath)
+		if err != nil {
+			log.Fatalf("Failed to open SQLite database: %v", err)
+		}
+		defer db.Close()
+		if storageID, err = sqlite.DatabaseID(rootCtx, db); err != nil {
+			log.Fatalf("Failed to read SQLite database ID: %v", err)
+		}
+		if _, err := sqlite.CheckSameDatabase(rootCtx, storageID, clusterObserver); err != nil {
+			log.Fatalf("SQLite storage is single-host only: %v", err)
+		}
+		jobRepo = sqlite.NewSQLiteJobRepository(db, logger)
+		jobWatcher = sqlite.NewSQLiteJobWatcher(db, cfg.Storage.JobPollInterval, logger)
+		execRepo = sqlite.NewSQLiteExecutionRepository(db, logger)
+	default:
+		log.Fatalf("Unknown storage backend %q", cfg.Storage.Backend)
+	}
+	log.Printf("Using %s storage for jobs and execution records.", cfg.Storage.Backend)
+	fireTimeRepo := etcd.NewEtcdFireTimeRepository(etcdClient, logger)
+	retryQueue := etcd.NewEtcdRetryQueue(etcdClient, logger)
 
 	go discovery.WatchWorkers(rootCtx)
+	go healthChecker.Run(rootCtx, dispatcher)
 
-	cronScheduler := scheduler.NewCronScheduler(dispatcher, logger)
-	jobService := usecase.NewJobService(jobRepo, execRepo, cronScheduler, logger)
-	leaderManager := etcd.NewEtcdLeaderElectionManager(etcdClient, nodeID, cfg.EtcdTimeout, logger)
-	schedulerService := usecase.NewSchedularService(leaderManager, cronScheduler, jobRepo, nodeID) // leaderManager is not used here directly
+	cronScheduler := scheduler.NewCronScheduler(dispatcher, fireTimeRepo, logger)
+	apiAddr := cfg.APIAdvertiseAddr
+	if apiAddr == "" {
+		apiAddr = cfg.HttpListenAddr
+	}
+	leaderManager := etcd.NewEtcdLeaderElectionManager(etcdClient, nodeID, apiAddr, storageID, cfg.EtcdTimeout, logger)
+	jobService := usecase.NewJobService(jobRepo, execRepo, dispatcher, dispatcher, leaderManager, logger)
+	clusterService := usecase.NewClusterService(healthChecker, clusterObserver, nodeID, logger)
+	retryService := usecase.NewRetryService(retryQueue, jobRepo, execRepo, dispatcher, logger)
+	var execArchive domain.ExecutionArchive
+	if cfg.Retention.ArchiveDir != "" {
+		if execArchive, err = archive.NewFSExecutionArchive(cfg.Retention.ArchiveDir); err != nil {
+			log.Fatalf("Failed to create execution archive: %v", err)
+		}
+	}
+	retention := domain.RetentionPolicy{MaxCount: cfg.Retention.MaxCount, MaxAge: cfg.Retention.MaxAge}
+	historyCompactor := usecase.NewHistoryCompactor(jobRepo, execRepo, execArchive, retention, cfg.Retention.Interval, cfg.Retention.BatchSize, logger)
+	leaderTasks = append(leaderTasks, retryService, historyCompactor)
+	schedulerService := usecase.NewSchedularService(leaderManager, cronScheduler, jobRepo, jobWatcher, nodeID, leaderTasks...) // leaderManager is not used here directly
 
-	jobHandler := http_api.NewJobHandler(jobService, logger)
+	jobHandler := http_api.NewJobHandler(jobService, clusterService, logger)
+	clusterHandler := http_api.NewClusterHandler(clusterService, logger)
 
 	// 10. Register routes and metrics endpoint
 	mux := http.NewServeMux()
 	mux.Handle("/metrics", promhttp.Handler())
 	jobHandler.RegisterRoutes(mux)
+	clusterHandler.RegisterRoutes(mux)
 
 	// 11. Start SchedulerService
 	go func() {
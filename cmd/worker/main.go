@@ -8,6 +8,7 @@ import (
 	"net"
 	"os"
 	"os/signal"
+	"runtime"
 	"syscall"
 	"time"
 
@@ -15,13 +16,17 @@ import (
 	"distributed-cron/internal/domain"
 	"distributed-cron/internal/infra/etcd"
 	http_infra "distributed-cron/internal/infra/http"
+	"distributed-cron/internal/infra/logstore"
 	shell_infra "distributed-cron/internal/infra/shell"
+	"distributed-cron/internal/infra/sqlite"
 	"distributed-cron/internal/worker"
 	pb "distributed-cron/proto"
 
 	"github.com/google/uuid"
 	otelgrpc "go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func main() {
@@ -58,7 +63,12 @@ func main() {
 	registry := worker.NewRegistry(etcdClient, logger)
 	regCtx, regCancel := context.WithTimeout(rootCtx, 5*time.Second)
 	defer regCancel()
-	err = registry.Register(regCtx, workerID, grpcListenAddr, int64(cfg.LeaderElectionTTL.Seconds()))
+	capacity := cfg.Worker.Capacity
+	if capacity <= 0 {
+		capacity = runtime.NumCPU()
+	}
+	registration := domain.WorkerRegistration{Addr: grpcListenAddr, Capacity: capacity, Labels: cfg.Worker.Labels}
+	err = registry.Register(regCtx, workerID, registration, int64(cfg.LeaderElectionTTL.Seconds()))
 	if err != nil {
 		log.Fatalf("Failed to register worker: %v", err)
 	}
@@ -73,9 +83,38 @@ func main() {
 
 	// 6. Instantiate executors, locker, and execution repository
 	httpExecutor := http_infra.NewHttpTaskExecutor()
-	shellExecutor := shell_infra.NewShellTaskExecutor(logger)
+	shellExecutor := shell_infra.NewShellTaskExecutor(cfg.CgroupParent, logger)
 	locker := etcd.NewEtcdLocker(etcdClient)
-	execRepo := etcd.NewEtcdExecutionRepository(etcdClient, logger) // Instantiate execution repository
+	var execRepo domain.ExecutionRepository
+	switch cfg.Storage.Backend {
+	case config.StorageBackendEtcd:
+		execRepo = etcd.NewEtcdExecutionRepository(etcdClient, logger)
+	case config.StorageBackendSQLite:
+		db, err := sqlite.Open(rootCtx, cfg.Storage.SQLitePath)
+		if err != nil {
+			log.Fatalf("Failed to open SQLite database: %v", err)
+		}
+		defer db.Close()
+		databaseID, err := sqlite.DatabaseID(rootCtx, db)
+		if err != nil {
+			log.Fatalf("Failed to read SQLite database ID: %v", err)
+		}
+		checked, err := sqlite.CheckSameDatabase(rootCtx, databaseID, etcd.NewEtcdClusterObserver(etcdClient))
+		if err != nil {
+			log.Fatalf("SQLite storage is single-host only: %v", err)
+		}
+		if !checked {
+			logger.Warn("no master running to check the SQLite database against; masters and workers must share one file on one host", "path", cfg.Storage.SQLitePath)
+		}
+		execRepo = sqlite.NewSQLiteExecutionRepository(db, logger)
+	default:
+		log.Fatalf("Unknown storage backend %q", cfg.Storage.Backend)
+	}
+	retryQueue := etcd.NewEtcdRetryQueue(etcdClient, logger)
+	logStore, err := logstore.NewFSLogStore(cfg.LogDir)
+	if err != nil {
+		log.Fatalf("Failed to create log store: %v", err)
+	}
 	executors := map[domain.ExecutorType]domain.TaskExecutor{
 		domain.ExecutorTypeHTTP:  httpExecutor,
 		domain.ExecutorTypeShell: shellExecutor,
@@ -87,11 +126,23 @@ func main() {
 		log.Fatalf("Failed to listen for gRPC: %v", err)
 	}
 
-	workerServer := worker.NewServer(executors, locker, execRepo, workerID, logger) // Inject execRepo
+	workerServer := worker.NewServer(executors, locker, execRepo, retryQueue, logStore, cfg.OutputLimitBytes, workerID, logger) // Inject execRepo
 	grpcServer := grpc.NewServer(
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
 	)
 	pb.RegisterWorkerServer(grpcServer, workerServer)
+	healthServer := health.NewServer() // Masters probe it to detect wedged workers
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+
+	// Delete execution output that outlived log_max_age
+	if cfg.LogMaxAge > 0 {
+		go workerServer.PruneLogs(rootCtx, cfg.LogMaxAge)
+	}
+
+	// Publish the running-execution count so the master can balance load
+	if cfg.Worker.HeartbeatInterval > 0 {
+		go registry.Heartbeat(rootCtx, cfg.Worker.HeartbeatInterval, workerServer.Running)
+	}
 
 	log.Printf("gRPC server listening on %s", grpcListenAddr)
 	go func() {
@@ -104,6 +155,7 @@ func main() {
 	<-rootCtx.Done()
 	log.Println("Shutting down worker node gracefully...")
 
+	healthServer.Shutdown()
 	grpcServer.GracefulStop()
 
 	log.Println("Worker node shut down.")
@@ -1,6 +1,7 @@
 // frontend/src/services/apiService.ts
 import axios from 'axios';
-import type { Job } from '../types/Job'; // Assuming Job type is now in @/types/Job
+import type { Job, ExecutionRecord } from '../types/Job'; // Assuming Job type is now in @/types/Job
+import type { WorkerStatus, ClusterStatus } from '../types/Worker';
 
 // Define the base URL of our Go backend API
 const apiClient = axios.create({
@@ -10,6 +11,19 @@ const apiClient = axios.create({
   },
 });
 
+export interface HistoryPage {
+  records: ExecutionRecord[];
+  nextCursor: string;
+}
+
+export interface ExecutionFilters {
+  job?: string;
+  status?: ExecutionRecord['status'];
+  worker?: string;
+  since?: string; // RFC 3339 time or a duration before now, e.g. "1h"
+  until?: string;
+}
+
 export type SaveJobPayload = Omit<Job, 'id' | 'created_at' | 'updated_at'>;
 
 export const apiService = {
@@ -30,11 +44,49 @@ export const apiService = {
     return response.data;
   },
 
-  // Fetch job history
-  async getJobHistory(jobName: string, page: number = 1, pageSize: number = 20): Promise<any[]> {
+  // Fetch a page of job history; nextCursor is empty on the last page
+  async getJobHistory(jobName: string, cursor: string = '', pageSize: number = 20): Promise<HistoryPage> {
     const response = await apiClient.get(`/jobs/${jobName}/history`, {
-      params: { page, pageSize }
+      params: { cursor: cursor || undefined, pageSize }
+    });
+    return {
+      records: response.data || [],
+      nextCursor: response.headers['x-next-cursor'] || '',
+    };
+  },
+
+  // Query the executions of all jobs; empty filters are ignored
+  async queryExecutions(filters: ExecutionFilters, cursor: string = '', pageSize: number = 20): Promise<HistoryPage> {
+    const response = await apiClient.get('/executions', {
+      params: { ...filters, cursor: cursor || undefined, pageSize }
     });
+    return {
+      records: response.data || [],
+      nextCursor: response.headers['x-next-cursor'] || '',
+    };
+  },
+
+  // Fetch the full record of a single execution
+  async getExecution(jobName: string, executionId: string): Promise<ExecutionRecord> {
+    const response = await apiClient.get(`/jobs/${jobName}/executions/${executionId}`);
+    return response.data;
+  },
+
+  // Fetch the registered workers with their load and health
+  async getWorkers(): Promise<WorkerStatus[]> {
+    const response = await apiClient.get('/workers');
     return response.data || [];
+  },
+
+  // Fetch a single registered worker
+  async getWorker(id: string): Promise<WorkerStatus> {
+    const response = await apiClient.get(`/workers/${id}`);
+    return response.data;
+  },
+
+  // Fetch the current leader and all master candidates
+  async getCluster(): Promise<ClusterStatus> {
+    const response = await apiClient.get('/cluster');
+    return response.data;
   }
 };
@@ -7,13 +7,52 @@ export interface Job {
     executor: {
       url?: string;
       method?: string;
+      headers?: Record<string, string>;
+      body?: string;
+      auth?: {
+        type: 'basic' | 'bearer';
+        username?: string;
+        password?: string;
+        token?: string;
+      };
+      expected_status?: number[];
+      assertion?: {
+        type: 'substring' | 'regex' | 'jsonpath';
+        expression: string;
+        value?: string;
+      };
       command?: string;
+      env?: Record<string, string>;
+      working_dir?: string;
+      user?: string;
+      group?: string;
+      kill_grace_period?: string;
+      limits?: {
+        cpu?: number;
+        memory_mb?: number;
+        pids?: number;
+      };
     };
     concurrency_policy?: 'Allow' | 'Forbid';
     retry_policy?: {
       max_retries: number;
       backoff: string;
+      multiplier?: number;
+      max_backoff?: string;
+      jitter?: number;
+      retry_on_different_worker?: boolean;
     };
+    retention?: {
+      max_count?: number;
+      max_age?: string;
+    };
+    node_selector?: Record<string, string>;
+    affinity?: {
+      key: string;
+      operator: 'In' | 'NotIn' | 'Exists' | 'DoesNotExist';
+      values?: string[];
+    }[];
+    paused?: boolean;
     created_at: string;
     updated_at: string;
   }
@@ -23,9 +62,25 @@ export interface Job {
     job_name: string;
     start_time: string;
     end_time: string;
-    status: 'running' | 'success' | 'failed';
+    status: 'running' | 'success' | 'failed' | 'cancelled' | 'timed_out' | 'retry_pending';
     output?: string;
     error?: string;
     retries_attempted: number;
     worker_id?: string;
+    exit_code?: number;
+    signal?: string;
+    failure_reason?: 'oom_killed';
+    duration_ms: number;
+    user_cpu_ms?: number;
+    system_cpu_ms?: number;
+    max_rss_bytes?: number;
+    output_bytes: number;
+    http_status?: number;
+    attempts?: {
+      attempt: number;
+      worker_id: string;
+      start_time: string;
+      end_time: string;
+      error?: string;
+    }[];
   }
@@ -0,0 +1,30 @@
+// frontend/src/types/Worker.ts
+export interface WorkerStatus {
+    id: string;
+    addr: string;
+    running: number;
+    capacity: number;
+    labels?: Record<string, string>;
+    health: {
+      circuit: 'closed' | 'open' | 'half_open';
+      consecutive_failures: number;
+      last_error?: string;
+      last_probe_at?: string;
+      open_until?: string;
+    };
+    registered_at?: string;
+    lease_ttl_seconds: number;
+    last_heartbeat?: string;
+  }
+
+  export interface ClusterStatus {
+    node_id: string;
+    leader?: string;
+    candidates: {
+      node_id: string;
+      api_addr?: string;
+      storage_id?: string;
+      leader: boolean;
+      lease_ttl_seconds: number;
+    }[];
+  }
@@ -0,0 +1,123 @@
+// internal/api/http/cluster_handler.go
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"distributed-cron/internal/domain"
+	"distributed-cron/internal/usecase"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ClusterHandler serves the worker and cluster endpoints.
+type ClusterHandler struct {
+	service *usecase.ClusterService
+	logger  *slog.Logger
+	tracer  trace.Tracer
+}
+
+// NewClusterHandler creates a new ClusterHandler.
+func NewClusterHandler(service *usecase.ClusterService, logger *slog.Logger) *ClusterHandler {
+	return &ClusterHandler{
+		service: service,
+		logger:  logger.With("component", "cluster-handler"),
+		tracer:  otel.Tracer("distributed-cron-api"),
+	}
+}
+
+// RegisterRoutes registers worker and cluster routes to the http.ServeMux.
+func (h *ClusterHandler) RegisterRoutes(mux *http.ServeMux) {
+	mux.Handle("/workers", instrument(h.tracer, http.HandlerFunc(h.handleListWorkers), func(*http.Request) string {
+		return "/workers"
+	}))
+	mux.Handle("/workers/", instrument(h.tracer, http.HandlerFunc(h.handleGetWorker), func(*http.Request) string {
+		return "/workers/{id}"
+	}))
+	mux.Handle("/cluster", instrument(h.tracer, http.HandlerFunc(h.handleGetCluster), func(*http.Request) string {
+		return "/cluster"
+	}))
+}
+
+// handleListWorkers handles GET /workers.
+func (h *ClusterHandler) handleListWorkers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx, span := h.tracer.Start(r.Context(), "handler.ListWorkers")
+	defer span.End()
+
+	workers, err := h.service.ListWorkers(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to list workers in service")
+		span.RecordError(err)
+		h.logger.Error("error listing workers", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(workers)
+}
+
+// handleGetWorker handles GET /workers/{id}.
+func (h *ClusterHandler) handleGetWorker(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	workerID := strings.TrimPrefix(r.URL.Path, "/workers/")
+	if workerID == "" || strings.Contains(workerID, "/") {
+		http.NotFound(w, r)
+		return
+	}
+	ctx, span := h.tracer.Start(r.Context(), "handler.GetWorker")
+	defer span.End()
+	span.SetAttributes(attribute.String("worker.id", workerID))
+
+	worker, err := h.service.GetWorker(ctx, workerID)
+	if err != nil {
+		if errors.Is(err, domain.ErrWorkerNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		span.SetStatus(codes.Error, "Failed to get worker in service")
+		span.RecordError(err)
+		h.logger.Error("error getting worker", "worker_id", workerID, "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(worker)
+}
+
+// handleGetCluster handles GET /cluster.
+func (h *ClusterHandler) handleGetCluster(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx, span := h.tracer.Start(r.Context(), "handler.GetCluster")
+	defer span.End()
+
+	cluster, err := h.service.GetCluster(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to get cluster in service")
+		span.RecordError(err)
+		h.logger.Error("error getting cluster status", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(cluster)
+}
@@ -2,30 +2,132 @@ package http
 
 import (
 	"distributed-cron/internal/domain"
+	"encoding/json"
+	"maps"
+	"strings"
 	"time"
 )
 
 // ExecutorRequest is the DTO for executor configuration.
 type ExecutorRequest struct {
-	URL     string `json:"url"`
-	Method  string `json:"method"`
-	Command string `json:"command"`
+	URL             string                    `json:"url"`
+	Method          string                    `json:"method"`
+	Headers         map[string]string         `json:"headers" validate:"omitempty,dive,keys,required,endkeys"`
+	Body            string                    `json:"body"`                                    // Raw request body
+	JSONBody        json.RawMessage           `json:"json_body" validate:"excluded_with=Body"` // JSON request body, sent with Content-Type application/json
+	Auth            *HTTPAuthRequest          `json:"auth,omitempty"`
+	ExpectedStatus  []int                     `json:"expected_status" validate:"omitempty,dive,gte=100,lte=599"`
+	Assertion       *ResponseAssertionRequest `json:"assertion,omitempty"`
+	Command         string                    `json:"command"`
+	Env             map[string]string         `json:"env" validate:"omitempty,dive,keys,required,excludesall==,endkeys"`
+	WorkingDir      string                    `json:"working_dir" validate:"omitempty,startswith=/"`
+	User            string                    `json:"user"`
+	Group           string                    `json:"group"`
+	KillGracePeriod string                    `json:"kill_grace_period" validate:"omitempty,duration"`
+	Limits          *ResourceLimitsRequest    `json:"limits,omitempty"`
+}
+
+// ResourceLimitsRequest is the DTO for the resource limits of a shell job.
+type ResourceLimitsRequest struct {
+	CPU      float64 `json:"cpu" validate:"gte=0"`
+	MemoryMB int64   `json:"memory_mb" validate:"gte=0"`
+	Pids     int64   `json:"pids" validate:"gte=0"`
+}
+
+// HTTPAuthRequest is the DTO for the credentials of an HTTP job.
+type HTTPAuthRequest struct {
+	Type     string `json:"type" validate:"required,oneof=basic bearer"`
+	Username string `json:"username" validate:"required_if=Type basic"`
+	Password string `json:"password"`
+	Token    string `json:"token" validate:"required_if=Type bearer"`
+}
+
+// redactedSecret replaces the password and token of an HTTP job in API responses. Saving
+// a job with it in their place keeps the stored credential.
+const redactedSecret = "******"
+
+// jobResponse returns a copy of job with its credentials redacted, for API responses.
+func jobResponse(job *domain.Job) *domain.Job {
+	if job.Executor.Auth == nil {
+		return job
+	}
+	redacted := *job
+	auth := *job.Executor.Auth
+	auth.Password = redact(auth.Password)
+	auth.Token = redact(auth.Token)
+	redacted.Executor.Auth = &auth
+	return &redacted
+}
+
+func jobResponses(jobs []*domain.Job) []*domain.Job {
+	redacted := make([]*domain.Job, len(jobs))
+	for i, job := range jobs {
+		redacted[i] = jobResponse(job)
+	}
+	return redacted
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return redactedSecret
+}
+
+// ResponseAssertionRequest is the DTO for the check applied to an HTTP job's response body.
+type ResponseAssertionRequest struct {
+	Type       string `json:"type" validate:"required,oneof=substring regex jsonpath"`
+	Expression string `json:"expression" validate:"required"`
+	Value      string `json:"value"`
+}
+
+func (r *ResponseAssertionRequest) toDomain() *domain.ResponseAssertion {
+	return &domain.ResponseAssertion{
+		Type:       domain.AssertionType(r.Type),
+		Expression: r.Expression,
+		Value:      r.Value,
+	}
 }
 
 // RetryPolicyRequest is the DTO for retry policy configuration.
 type RetryPolicyRequest struct {
-	MaxRetries int    `json:"max_retries" validate:"gte=0,lte=10"`
-	Backoff    string `json:"backoff" validate:"required_with=MaxRetries,duration"`
+	MaxRetries             int     `json:"max_retries" validate:"gte=0,lte=10"`
+	Backoff                string  `json:"backoff" validate:"required_with=MaxRetries,duration"`
+	Multiplier             float64 `json:"multiplier" validate:"gte=0,lte=10"`
+	MaxBackoff             string  `json:"max_backoff" validate:"omitempty,duration"`
+	Jitter                 float64 `json:"jitter" validate:"gte=0,lte=1"`
+	RetryOnDifferentWorker bool    `json:"retry_on_different_worker"`
+}
+
+// RetentionRequest is the DTO for the history retention of a job.
+type RetentionRequest struct {
+	MaxCount int    `json:"max_count" validate:"gte=0"`
+	MaxAge   string `json:"max_age" validate:"omitempty,duration"`
+}
+
+// LabelRequirementRequest is the DTO for an affinity rule of a job.
+type LabelRequirementRequest struct {
+	Key      string   `json:"key" validate:"required"`
+	Operator string   `json:"operator" validate:"required,oneof=In NotIn Exists DoesNotExist"`
+	Values   []string `json:"values"`
 }
 
 // SaveJobRequest is the Data Transfer Object for creating/updating a job.
 type SaveJobRequest struct {
-	Name              string              `json:"name" validate:"required,min=1,max=128"`
+	Name              string              `json:"name" validate:"required,min=1,max=128,ne=.,ne=.."`
 	CronExpr          string              `json:"cron_expr" validate:"required,cron"`
 	ExecutorType      string              `json:"executor_type" validate:"required,oneof=http shell"`
 	Executor          ExecutorRequest     `json:"executor" validate:"required"`
 	ConcurrencyPolicy string              `json:"concurrency_policy" validate:"omitempty,oneof=Allow Forbid"`
 	RetryPolicy       *RetryPolicyRequest `json:"retry_policy,omitempty" validate:"omitempty,dive"`
+	TimeZone          string              `json:"time_zone" validate:"omitempty,timezone"`
+	MisfirePolicy     string              `json:"misfire_policy" validate:"omitempty,oneof=Skip FireOnce FireAll"`
+	MaxMisfireRuns    int                 `json:"max_misfire_runs" validate:"gte=0,lte=1000"`
+	Timeout           string              `json:"timeout" validate:"omitempty,duration"`
+	Retention         *RetentionRequest   `json:"retention,omitempty" validate:"omitempty"`
+
+	NodeSelector map[string]string         `json:"node_selector" validate:"omitempty,dive,keys,required,endkeys"`
+	Affinity     []LabelRequirementRequest `json:"affinity" validate:"omitempty,dive"`
 }
 
 // ToDomainJob converts a SaveJobRequest DTO to a domain.Job object.
@@ -38,12 +140,34 @@ func (r *SaveJobRequest) ToDomainJob() *domain.Job {
 	var retryPolicy *domain.RetryPolicy
 	if r.RetryPolicy != nil {
 		backoff, _ := time.ParseDuration(r.RetryPolicy.Backoff)
+		maxBackoff, _ := time.ParseDuration(r.RetryPolicy.MaxBackoff)
 		retryPolicy = &domain.RetryPolicy{
-			MaxRetries: r.RetryPolicy.MaxRetries,
-			Backoff:    backoff,
+			MaxRetries:             r.RetryPolicy.MaxRetries,
+			Backoff:                backoff,
+			Multiplier:             r.RetryPolicy.Multiplier,
+			MaxBackoff:             maxBackoff,
+			Jitter:                 r.RetryPolicy.Jitter,
+			RetryOnDifferentWorker: r.RetryPolicy.RetryOnDifferentWorker,
 		}
 	}
 
+	timeout, _ := time.ParseDuration(r.Timeout)
+
+	var retention *domain.RetentionPolicy
+	if r.Retention != nil {
+		maxAge, _ := time.ParseDuration(r.Retention.MaxAge)
+		retention = &domain.RetentionPolicy{MaxCount: r.Retention.MaxCount, MaxAge: maxAge}
+	}
+
+	var affinity []domain.LabelRequirement
+	for _, a := range r.Affinity {
+		affinity = append(affinity, domain.LabelRequirement{
+			Key:      a.Key,
+			Operator: domain.LabelOperator(a.Operator),
+			Values:   a.Values,
+		})
+	}
+
 	// Normalize executor based on type
 	executor := domain.JobExecutor{}
 	executorType := domain.ExecutorType(r.ExecutorType)
@@ -54,11 +178,46 @@ func (r *SaveJobRequest) ToDomainJob() *domain.Job {
 		if executor.Method == "" {
 			executor.Method = "GET"
 		}
+		executor.Headers = r.Executor.Headers
+		executor.Body = r.Executor.Body
+		if len(r.Executor.JSONBody) > 0 {
+			executor.Body = string(r.Executor.JSONBody)
+			if !hasHeader(executor.Headers, "Content-Type") {
+				executor.Headers = maps.Clone(executor.Headers)
+				if executor.Headers == nil {
+					executor.Headers = make(map[string]string)
+				}
+				executor.Headers["Content-Type"] = "application/json"
+			}
+		}
+		if auth := r.Executor.Auth; auth != nil {
+			executor.Auth = &domain.HTTPAuth{
+				Type:     domain.HTTPAuthType(auth.Type),
+				Username: auth.Username,
+				Password: auth.Password,
+				Token:    auth.Token,
+			}
+		}
+		executor.ExpectedStatus = r.Executor.ExpectedStatus
+		if r.Executor.Assertion != nil {
+			executor.Assertion = r.Executor.Assertion.toDomain()
+		}
 	case domain.ExecutorTypeShell:
 		executor.Command = r.Executor.Command
+		executor.Env = r.Executor.Env
+		executor.WorkingDir = r.Executor.WorkingDir
+		executor.User = r.Executor.User
+		executor.Group = r.Executor.Group
+		executor.KillGracePeriod, _ = time.ParseDuration(r.Executor.KillGracePeriod)
+		if limits := r.Executor.Limits; limits != nil {
+			executor.Limits = &domain.ResourceLimits{
+				CPU:      limits.CPU,
+				MemoryMB: limits.MemoryMB,
+				Pids:     limits.Pids,
+			}
+		}
 	}
 
-
 	return &domain.Job{
 		Name:              r.Name,
 		CronExpr:          r.CronExpr,
@@ -66,5 +225,34 @@ func (r *SaveJobRequest) ToDomainJob() *domain.Job {
 		Executor:          executor,
 		ConcurrencyPolicy: concurrencyPolicy,
 		RetryPolicy:       retryPolicy,
+		TimeZone:          r.TimeZone,
+		MisfirePolicy:     domain.MisfirePolicy(r.MisfirePolicy),
+		MaxMisfireRuns:    r.MaxMisfireRuns,
+		Timeout:           timeout,
+		Retention:         retention,
+		NodeSelector:      r.NodeSelector,
+		Affinity:          affinity,
+	}
+}
+
+// hasHeader reports whether headers contains name, compared case-insensitively.
+func hasHeader(headers map[string]string, name string) bool {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			return true
+		}
 	}
-}
\ No newline at end of file
+	return false
+}
+
+// TriggerJobRequest is the DTO for manually triggering a job. All fields are optional
+// one-off overrides that only apply to the triggered run.
+type TriggerJobRequest struct {
+	Command string            `json:"command,omitempty"`
+	Env     map[string]string `json:"env,omitempty" validate:"omitempty,dive,keys,required,excludesall==,endkeys"`
+}
+
+// TriggerJobResponse is returned after a job has been dispatched manually.
+type TriggerJobResponse struct {
+	ExecutionID string `json:"execution_id"`
+}
@@ -2,8 +2,12 @@
 package http
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -22,16 +26,25 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+// forwardedHeader marks a request a follower forwarded to the leader, with the node ID of
+// the follower, so it is not forwarded again.
+const forwardedHeader = "X-Cron-Forwarded-By"
+
+// forwardTimeout bounds a request forwarded to the leader, including its dispatch.
+const forwardTimeout = 30 * time.Second
+
 // JobHandler 负责处理与 Job 相关的 HTTP 请求。
 type JobHandler struct {
 	service  *usecase.JobService
+	cluster  *usecase.ClusterService // Finds the leader to forward leader-only requests to
+	client   *http.Client
 	logger   *slog.Logger
 	validate *validator.Validate
 	tracer   trace.Tracer
 }
 
 // NewJobHandler 创建一个新的 JobHandler，并初始化 validator。
-func NewJobHandler(service *usecase.JobService, logger *slog.Logger) *JobHandler {
+func NewJobHandler(service *usecase.JobService, cluster *usecase.ClusterService, logger *slog.Logger) *JobHandler {
 	validate := validator.New()
 
 	_ = validate.RegisterValidation("cron", func(fl validator.FieldLevel) bool {
@@ -45,8 +58,18 @@ func NewJobHandler(service *usecase.JobService, logger *slog.Logger) *JobHandler
 		return err == nil
 	})
 
+	// Assertion expressions are checked by the domain, so the API accepts exactly what workers can evaluate.
+	validate.RegisterStructValidation(func(sl validator.StructLevel) {
+		req := sl.Current().Interface().(ResponseAssertionRequest)
+		if req.toDomain().Validate() != nil {
+			sl.ReportError(req.Expression, "Expression", "expression", "assertion_expression", "")
+		}
+	}, ResponseAssertionRequest{})
+
 	return &JobHandler{
 		service:  service,
+		cluster:  cluster,
+		client:   &http.Client{Timeout: forwardTimeout},
 		logger:   logger.With("component", "job-handler"),
 		validate: validate,
 		tracer:   otel.Tracer("distributed-cron-api"),
@@ -64,17 +87,46 @@ func (w *instrumentedResponseWriter) WriteHeader(statusCode int) {
 	w.ResponseWriter.WriteHeader(statusCode)
 }
 
+// Flush lets streaming handlers flush through the instrumentation wrapper.
+func (w *instrumentedResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// flushWriter flushes after every write so streamed output reaches the client immediately.
+type flushWriter struct {
+	w http.ResponseWriter
+}
+
+func (fw flushWriter) Write(p []byte) (int, error) {
+	n, err := fw.w.Write(p)
+	if f, ok := fw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return n, err
+}
+
 // RegisterRoutes registers job-related routes to the http.ServeMux.
 func (h *JobHandler) RegisterRoutes(mux *http.ServeMux) {
-	baseHandler := http.HandlerFunc(h.handleJobs)
-
-	instrumentedHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		path := "/jobs/"
+	mux.Handle("/jobs/", instrument(h.tracer, http.HandlerFunc(h.handleJobs), func(r *http.Request) string {
 		if jobName := strings.TrimPrefix(r.URL.Path, "/jobs/"); jobName != "" {
-			path = "/jobs/{name}"
+			return "/jobs/{name}"
 		}
+		return "/jobs/"
+	}))
+	mux.Handle("/executions", instrument(h.tracer, http.HandlerFunc(h.handleQueryExecutions), func(*http.Request) string {
+		return "/executions"
+	}))
+}
+
+// instrument wraps baseHandler with a span and the request metric, labelled with the
+// route that route returns for the request.
+func instrument(tracer trace.Tracer, baseHandler http.Handler, route func(*http.Request) string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := route(r)
 
-		ctx, span := h.tracer.Start(r.Context(), "HTTP "+r.Method+" "+path, trace.WithAttributes(
+		ctx, span := tracer.Start(r.Context(), "HTTP "+r.Method+" "+path, trace.WithAttributes(
 			attribute.String("http.method", r.Method),
 			attribute.String("http.target", r.URL.Path),
 		))
@@ -92,8 +144,6 @@ func (h *JobHandler) RegisterRoutes(mux *http.ServeMux) {
 			span.SetStatus(codes.Error, "Server Error")
 		}
 	})
-
-	mux.Handle("/jobs/", instrumentedHandler)
 }
 
 // handleJobs is a general dispatcher for /jobs/ path
@@ -106,18 +156,31 @@ func (h *JobHandler) handleJobs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var jobName, action string
+	// e.g. /jobs/my-job/executions/{id}/cancel -> executionID "{id}", subAction "cancel"
+	var jobName, action, executionID, subAction string
 	if len(pathParts) > 1 {
 		jobName = pathParts[1]
 	}
 	if len(pathParts) > 2 {
 		action = pathParts[2]
 	}
+	if len(pathParts) > 3 {
+		executionID = pathParts[3]
+	}
+	if len(pathParts) > 4 {
+		subAction = pathParts[4]
+	}
 
 	switch r.Method {
 	case http.MethodGet:
 		if jobName != "" && action == "history" {
 			h.handleGetJobHistory(w, r, jobName)
+		} else if action == "executions" && executionID != "" && subAction == "" {
+			h.handleGetExecution(w, r, jobName, executionID)
+		} else if action == "executions" && executionID != "" && subAction == "logs" {
+			h.handleStreamExecutionLogs(w, r, jobName, executionID)
+		} else if action == "executions" && executionID != "" && subAction == "output" {
+			h.handleGetExecutionOutput(w, r, jobName, executionID)
 		} else if jobName != "" && action == "" {
 			h.handleGetJob(w, r, jobName)
 		} else if jobName == "" && action == "" {
@@ -126,7 +189,17 @@ func (h *JobHandler) handleJobs(w http.ResponseWriter, r *http.Request) {
 			http.NotFound(w, r)
 		}
 	case http.MethodPost, http.MethodPut:
-		h.handleSaveJob(w, r)
+		if jobName != "" && action == "trigger" && r.Method == http.MethodPost {
+			h.handleTriggerJob(w, r, jobName)
+		} else if jobName != "" && (action == "pause" || action == "resume") && r.Method == http.MethodPost {
+			h.handleSetJobPaused(w, r, jobName, action == "pause")
+		} else if action == "executions" && executionID != "" && subAction == "cancel" && r.Method == http.MethodPost {
+			h.handleCancelExecution(w, r, jobName, executionID)
+		} else if action == "" {
+			h.handleSaveJob(w, r)
+		} else {
+			http.NotFound(w, r)
+		}
 	case http.MethodDelete:
 		if jobName != "" && action == "" {
 			h.handleDeleteJob(w, r, jobName)
@@ -140,35 +213,197 @@ func (h *JobHandler) handleJobs(w http.ResponseWriter, r *http.Request) {
 
 // ... (handleSaveJob, handleDeleteJob, handleGetJob, handleListJobs remain the same) ...
 
-// handleGetJobHistory handles listing execution history for a job (GET /jobs/{name}/history)
+// handleGetJobHistory handles listing execution history for a job (GET /jobs/{name}/history).
+// Pages are requested with ?cursor=; the cursor of the next page is returned in the
+// X-Next-Cursor header, which is absent on the last page.
 func (h *JobHandler) handleGetJobHistory(w http.ResponseWriter, r *http.Request, name string) {
 	ctx, span := h.tracer.Start(r.Context(), "handler.GetJobHistory")
 	defer span.End()
 	span.SetAttributes(attribute.String("job.name", name))
 
 	// Parse pagination parameters
-	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
-	if page < 1 {
-		page = 1
-	}
+	cursor := r.URL.Query().Get("cursor")
 	pageSize, _ := strconv.Atoi(r.URL.Query().Get("pageSize"))
 	if pageSize <= 0 || pageSize > 100 {
 		pageSize = 20 // default and max page size
 	}
-	span.SetAttributes(attribute.Int("page", page), attribute.Int("page_size", pageSize))
-
+	span.SetAttributes(attribute.String("cursor", cursor), attribute.Int("page_size", pageSize))
 
-	history, err := h.service.ListHistory(ctx, name, page, pageSize)
+	history, next, err := h.service.ListHistory(ctx, name, cursor, pageSize)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		h.logger.Error("error listing job history", "job_name", name, "error", err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
 		return
 	}
 
+	if next != "" {
+		w.Header().Set("X-Next-Cursor", next)
+	}
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(history)
 }
 
+// handleQueryExecutions handles GET /executions?status=&job=&worker=&since=&until=, which
+// lists the executions of all jobs that match the filters, newest first. since and until
+// take an RFC 3339 time or a duration before now, e.g. since=1h. Pages work like the job
+// history: ?cursor= with the X-Next-Cursor header of the previous page.
+func (h *JobHandler) handleQueryExecutions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	ctx, span := h.tracer.Start(r.Context(), "handler.QueryExecutions")
+	defer span.End()
+
+	params := r.URL.Query()
+	q := domain.ExecutionQuery{
+		JobName:  params.Get("job"),
+		Status:   domain.ExecutionStatus(params.Get("status")),
+		WorkerID: params.Get("worker"),
+		Cursor:   params.Get("cursor"),
+	}
+	if q.Status != "" && !q.Status.IsValid() {
+		http.Error(w, fmt.Sprintf("invalid status %q", q.Status), http.StatusBadRequest)
+		return
+	}
+	var err error
+	if q.Since, err = parseQueryTime(params.Get("since")); err != nil {
+		http.Error(w, fmt.Sprintf("invalid since: %v", err), http.StatusBadRequest)
+		return
+	}
+	if q.Until, err = parseQueryTime(params.Get("until")); err != nil {
+		http.Error(w, fmt.Sprintf("invalid until: %v", err), http.StatusBadRequest)
+		return
+	}
+	q.Limit, _ = strconv.Atoi(params.Get("pageSize"))
+	if q.Limit <= 0 || q.Limit > 100 {
+		q.Limit = 20 // default and max page size
+	}
+	span.SetAttributes(
+		attribute.String("job.name", q.JobName),
+		attribute.String("status", string(q.Status)),
+		attribute.String("worker.id", q.WorkerID),
+		attribute.String("cursor", q.Cursor),
+		attribute.Int("page_size", q.Limit),
+	)
+
+	records, next, err := h.service.QueryExecutions(ctx, q)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidCursor) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		span.SetStatus(codes.Error, "Failed to query executions in service")
+		span.RecordError(err)
+		h.logger.Error("error querying executions", "error", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	if next != "" {
+		w.Header().Set("X-Next-Cursor", next)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(records)
+}
+
+// parseQueryTime parses an RFC 3339 time, or a duration that is subtracted from now.
+// An empty value is the zero time.
+func parseQueryTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// handleGetExecution returns the full record of a single execution (GET /jobs/{name}/executions/{id}).
+func (h *JobHandler) handleGetExecution(w http.ResponseWriter, r *http.Request, name, executionID string) {
+	ctx, span := h.tracer.Start(r.Context(), "handler.GetExecution")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", name), attribute.String("execution.id", executionID))
+
+	record, err := h.service.GetExecution(ctx, name, executionID)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to get execution from service")
+		span.RecordError(err)
+		h.logger.Warn("error getting execution", "job_name", name, "execution_id", executionID, "error", err)
+		if errors.Is(err, domain.ErrExecutionNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(record)
+}
+
+// handleStreamExecutionLogs handles GET /jobs/{name}/executions/{id}/logs[?follow=true].
+// The output is sent as a chunked plain-text response; with follow=true the response
+// stays open and relays output as the worker produces it.
+func (h *JobHandler) handleStreamExecutionLogs(w http.ResponseWriter, r *http.Request, name, executionID string) {
+	ctx, span := h.tracer.Start(r.Context(), "handler.StreamExecutionLogs")
+	defer span.End()
+	follow, _ := strconv.ParseBool(r.URL.Query().Get("follow"))
+	span.SetAttributes(
+		attribute.String("job.name", name),
+		attribute.String("execution.id", executionID),
+		attribute.Bool("follow", follow),
+	)
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	if err := h.service.StreamExecutionLogs(ctx, name, executionID, follow, flushWriter{w: w}); err != nil {
+		span.SetStatus(codes.Error, "Failed to stream execution logs")
+		span.RecordError(err)
+		h.logger.Error("error streaming execution logs", "job_name", name, "execution_id", executionID, "error", err)
+		// Only reported to the client if nothing has been streamed yet.
+		switch {
+		case errors.Is(err, domain.ErrExecutionNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, domain.ErrWorkerNotFound):
+			http.Error(w, err.Error(), http.StatusBadGateway)
+		default:
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+	}
+}
+
+// handleGetExecutionOutput returns the full output of an execution (GET /jobs/{name}/executions/{id}/output).
+func (h *JobHandler) handleGetExecutionOutput(w http.ResponseWriter, r *http.Request, name, executionID string) {
+	ctx, span := h.tracer.Start(r.Context(), "handler.GetExecutionOutput")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", name), attribute.String("execution.id", executionID))
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	if err := h.service.GetExecutionOutput(ctx, name, executionID, flushWriter{w: w}); err != nil {
+		span.SetStatus(codes.Error, "Failed to get execution output")
+		span.RecordError(err)
+		h.logger.Error("error getting execution output", "job_name", name, "execution_id", executionID, "error", err)
+		// Only reported to the client if nothing has been written yet.
+		switch {
+		case errors.Is(err, domain.ErrExecutionNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, domain.ErrWorkerNotFound):
+			http.Error(w, err.Error(), http.StatusBadGateway)
+		default:
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+	}
+}
+
 // handleSaveJob now uses DTO and validation
 func (h *JobHandler) handleSaveJob(w http.ResponseWriter, r *http.Request) {
 	ctx, span := h.tracer.Start(r.Context(), "handler.SaveJob")
@@ -203,6 +438,18 @@ func (h *JobHandler) handleSaveJob(w http.ResponseWriter, r *http.Request) {
 	job := req.ToDomainJob()
 	span.SetAttributes(attribute.String("job.name", job.Name))
 
+	if err := h.keepRedactedSecrets(ctx, job); err != nil {
+		span.SetStatus(codes.Error, "Failed to restore redacted credentials")
+		span.RecordError(err)
+		if errors.Is(err, domain.ErrJobNotFound) {
+			http.Error(w, "auth credentials are redacted; send the actual password or token", http.StatusBadRequest)
+		} else {
+			h.logger.Error("error loading job to restore redacted credentials", "job_name", job.Name, "error", err)
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
 	if err := h.service.Save(ctx, job); err != nil {
 		span.SetStatus(codes.Error, "Failed to save job in service")
 		span.RecordError(err)
@@ -213,7 +460,197 @@ func (h *JobHandler) handleSaveJob(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(job)
+	json.NewEncoder(w).Encode(jobResponse(job))
+}
+
+// keepRedactedSecrets replaces credentials that a client sent back redacted, e.g. when
+// saving a job it read from the API, with those of the stored job.
+func (h *JobHandler) keepRedactedSecrets(ctx context.Context, job *domain.Job) error {
+	auth := job.Executor.Auth
+	if auth == nil || (auth.Password != redactedSecret && auth.Token != redactedSecret) {
+		return nil
+	}
+	stored, err := h.service.Get(ctx, job.Name)
+	if err != nil {
+		return err
+	}
+	var storedAuth domain.HTTPAuth
+	if stored.Executor.Auth != nil {
+		storedAuth = *stored.Executor.Auth
+	}
+	if auth.Password == redactedSecret {
+		auth.Password = storedAuth.Password
+	}
+	if auth.Token == redactedSecret {
+		auth.Token = storedAuth.Token
+	}
+	return nil
+}
+
+// handleTriggerJob dispatches a job immediately (POST /jobs/{name}/trigger).
+// The optional body carries one-off overrides for this run only. Only the leader
+// dispatches, so a follower forwards the request to the leader and relays its response.
+func (h *JobHandler) handleTriggerJob(w http.ResponseWriter, r *http.Request, name string) {
+	ctx, span := h.tracer.Start(r.Context(), "handler.TriggerJob")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", name))
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to read request body")
+		span.RecordError(err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req TriggerJobRequest
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		span.SetStatus(codes.Error, "Failed to decode request body")
+		span.RecordError(err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.validate.Struct(req); err != nil {
+		span.SetStatus(codes.Error, "Validation failed")
+		span.RecordError(err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	executionID, err := h.service.Trigger(ctx, name, usecase.TriggerOverrides{
+		Command: req.Command,
+		Env:     req.Env,
+	})
+	if errors.Is(err, domain.ErrNotLeader) && r.Header.Get(forwardedHeader) == "" {
+		h.forwardToLeader(ctx, w, r, body)
+		return
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to trigger job in service")
+		span.RecordError(err)
+		h.logger.Error("error triggering job", "job_name", name, "error", err)
+		switch {
+		case errors.Is(err, domain.ErrJobNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, usecase.ErrOverrideNotSupported):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, domain.ErrTaskRejected), errors.Is(err, domain.ErrNoEligibleWorker):
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+		case errors.Is(err, domain.ErrNotLeader):
+			// Forwarded by a follower that took this master for the leader.
+			http.Error(w, "Manual triggers are dispatched by the leader; see GET /cluster", http.StatusServiceUnavailable)
+		default:
+			http.Error(w, "Failed to dispatch job", http.StatusServiceUnavailable)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(TriggerJobResponse{ExecutionID: executionID})
+}
+
+// forwardToLeader sends a request only the leader serves to the leader's API address and
+// relays its response.
+func (h *JobHandler) forwardToLeader(ctx context.Context, w http.ResponseWriter, r *http.Request, body []byte) {
+	ctx, span := h.tracer.Start(ctx, "handler.ForwardToLeader")
+	defer span.End()
+
+	cluster, err := h.cluster.GetCluster(ctx)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to look up the leader")
+		span.RecordError(err)
+		h.logger.Error("error looking up the leader to forward to", "path", r.URL.Path, "error", err)
+		http.Error(w, "Failed to look up the leader", http.StatusServiceUnavailable)
+		return
+	}
+	if len(cluster.Candidates) == 0 || cluster.Leader == cluster.NodeID || cluster.Candidates[0].APIAddr == "" {
+		// No leader yet, leadership is changing hands, or the leader does not publish its address.
+		span.SetStatus(codes.Error, "No leader to forward to")
+		http.Error(w, fmt.Sprintf("This master is not the leader and cannot forward to it (leader: %q); see GET /cluster", cluster.Leader), http.StatusServiceUnavailable)
+		return
+	}
+	leader := cluster.Candidates[0]
+	span.SetAttributes(attribute.String("cluster.leader", leader.NodeID), attribute.String("leader.api_addr", leader.APIAddr))
+
+	fwd, err := http.NewRequestWithContext(ctx, r.Method, "http://"+leader.APIAddr+r.URL.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to build forwarded request")
+		span.RecordError(err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	fwd.Header.Set("Content-Type", r.Header.Get("Content-Type"))
+	fwd.Header.Set(forwardedHeader, cluster.NodeID)
+
+	resp, err := h.client.Do(fwd)
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to forward request to the leader")
+		span.RecordError(err)
+		h.logger.Error("error forwarding request to the leader", "path", r.URL.Path, "leader", leader.NodeID, "leader_addr", leader.APIAddr, "error", err)
+		http.Error(w, fmt.Sprintf("Failed to forward to the leader at %s", leader.APIAddr), http.StatusBadGateway)
+		return
+	}
+	defer resp.Body.Close()
+
+	w.Header().Set("Content-Type", resp.Header.Get("Content-Type"))
+	w.WriteHeader(resp.StatusCode)
+	io.Copy(w, resp.Body)
+}
+
+// handleSetJobPaused handles POST /jobs/{name}/pause and POST /jobs/{name}/resume.
+func (h *JobHandler) handleSetJobPaused(w http.ResponseWriter, r *http.Request, name string, paused bool) {
+	ctx, span := h.tracer.Start(r.Context(), "handler.SetJobPaused")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", name), attribute.Bool("job.paused", paused))
+
+	var (
+		job *domain.Job
+		err error
+	)
+	if paused {
+		job, err = h.service.Pause(ctx, name)
+	} else {
+		job, err = h.service.Resume(ctx, name)
+	}
+	if err != nil {
+		span.SetStatus(codes.Error, "Failed to change job pause state in service")
+		span.RecordError(err)
+		h.logger.Error("error changing job pause state", "job_name", name, "paused", paused, "error", err)
+		if errors.Is(err, domain.ErrJobNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(jobResponse(job))
+}
+
+// handleCancelExecution handles POST /jobs/{name}/executions/{id}/cancel.
+func (h *JobHandler) handleCancelExecution(w http.ResponseWriter, r *http.Request, name, executionID string) {
+	ctx, span := h.tracer.Start(r.Context(), "handler.CancelExecution")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", name), attribute.String("execution.id", executionID))
+
+	if err := h.service.CancelExecution(ctx, name, executionID); err != nil {
+		span.SetStatus(codes.Error, "Failed to cancel execution in service")
+		span.RecordError(err)
+		h.logger.Error("error cancelling execution", "job_name", name, "execution_id", executionID, "error", err)
+		switch {
+		case errors.Is(err, domain.ErrExecutionNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, domain.ErrExecutionNotRunning):
+			http.Error(w, err.Error(), http.StatusConflict)
+		case errors.Is(err, domain.ErrWorkerNotFound):
+			http.Error(w, err.Error(), http.StatusBadGateway)
+		default:
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
 }
 
 func (h *JobHandler) handleDeleteJob(w http.ResponseWriter, r *http.Request, name string) {
@@ -250,7 +687,7 @@ func (h *JobHandler) handleGetJob(w http.ResponseWriter, r *http.Request, name s
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(job)
+	json.NewEncoder(w).Encode(jobResponse(job))
 }
 
 func (h *JobHandler) handleListJobs(w http.ResponseWriter, r *http.Request) {
@@ -267,5 +704,5 @@ func (h *JobHandler) handleListJobs(w http.ResponseWriter, r *http.Request) {
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(jobs)
+	json.NewEncoder(w).Encode(jobResponses(jobs))
 }
@@ -10,10 +10,85 @@ import (
 // Config holds all configuration for our application.
 // The mapstructure tags are used by Viper to unmarshal the data.
 type Config struct {
-	EtcdEndpoints      []string      `mapstructure:"etcd_endpoints"`
-	EtcdTimeout        time.Duration `mapstructure:"etcd_timeout"`
-	HttpListenAddr     string        `mapstructure:"http_listen_addr"`
-	LeaderElectionTTL  time.Duration `mapstructure:"leader_election_ttl"`
+	EtcdEndpoints     []string      `mapstructure:"etcd_endpoints"`
+	EtcdTimeout       time.Duration `mapstructure:"etcd_timeout"`
+	HttpListenAddr    string        `mapstructure:"http_listen_addr"`
+	LeaderElectionTTL time.Duration `mapstructure:"leader_election_ttl"`
+	// APIAdvertiseAddr is the address other masters reach this master's HTTP API at, to
+	// forward requests only the leader serves. Empty means HttpListenAddr.
+	APIAdvertiseAddr string `mapstructure:"api_advertise_addr"`
+	// CgroupParent is the cgroup v2 directory under which workers create one cgroup per
+	// shell execution that has resource limits. Empty disables resource limits.
+	CgroupParent string `mapstructure:"cgroup_parent"`
+	// LogDir is where workers keep the full output of executions.
+	LogDir string `mapstructure:"log_dir"`
+	// LogMaxAge is how long workers keep the output of an execution after it was last
+	// written; 0 keeps it forever.
+	LogMaxAge time.Duration `mapstructure:"log_max_age"`
+	// OutputLimitBytes caps the output kept in an execution record; the first and last
+	// halves are kept and the rest is only available from the log store.
+	OutputLimitBytes int `mapstructure:"output_limit_bytes"`
+	// Retention bounds the execution history kept in storage.
+	Retention RetentionConfig `mapstructure:"retention"`
+	// Storage selects where jobs and execution records are stored.
+	Storage StorageConfig `mapstructure:"storage"`
+	// Dispatch controls how the leader picks a worker for each execution.
+	Dispatch DispatchConfig `mapstructure:"dispatch"`
+	// Worker holds the settings a worker publishes about itself.
+	Worker WorkerConfig `mapstructure:"worker"`
+	// Health controls how masters probe workers and when they stop selecting them.
+	Health HealthConfig `mapstructure:"health"`
+}
+
+// HealthConfig holds the worker health probing and circuit breaker settings of the master.
+type HealthConfig struct {
+	ProbeInterval    time.Duration `mapstructure:"probe_interval"`    // Time between gRPC health probes; 0 disables probing
+	ProbeTimeout     time.Duration `mapstructure:"probe_timeout"`     // Deadline of one probe
+	FailureThreshold int           `mapstructure:"failure_threshold"` // Consecutive probe or dispatch failures that open a worker's circuit
+	Cooldown         time.Duration `mapstructure:"cooldown"`          // How long an open circuit keeps the worker out of selection
+}
+
+// DispatchConfig controls worker selection on the master.
+type DispatchConfig struct {
+	Strategy string `mapstructure:"strategy"` // "least_loaded", "round_robin", "power_of_two" or "random"
+	// Timeout bounds one dispatch, including failovers to other workers when a worker
+	// cannot be reached.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// WorkerConfig holds the load information a worker publishes in its registration.
+type WorkerConfig struct {
+	Capacity          int           `mapstructure:"capacity"`           // Executions the worker is sized for; 0 means the number of CPUs
+	HeartbeatInterval time.Duration `mapstructure:"heartbeat_interval"` // How often the running count is checked and republished
+	// Labels describe the worker, e.g. zone: dmz, for jobs to select it with node selectors
+	// and affinity rules. Names are read in lower case.
+	Labels map[string]string `mapstructure:"labels"`
+}
+
+// Storage backends.
+const (
+	StorageBackendEtcd   = "etcd"
+	StorageBackendSQLite = "sqlite"
+)
+
+// StorageConfig selects the backend of the job and execution repositories. etcd stays
+// responsible for leader election, worker discovery and locks with either backend.
+type StorageConfig struct {
+	Backend string `mapstructure:"backend"` // "etcd" or "sqlite"
+	// SQLitePath is the database file. All masters and workers must run on one host and
+	// use the same file; they refuse to start if the running masters use another one.
+	SQLitePath string `mapstructure:"sqlite_path"`
+	// JobPollInterval is how often the leader polls SQLite for job changes made through other masters.
+	JobPollInterval time.Duration `mapstructure:"job_poll_interval"`
+}
+
+// RetentionConfig holds the global history retention settings, which jobs may override.
+type RetentionConfig struct {
+	MaxCount   int           `mapstructure:"max_count"`   // Newest records kept per job; 0 means unbounded
+	MaxAge     time.Duration `mapstructure:"max_age"`     // Older records are deleted; 0 means unbounded
+	Interval   time.Duration `mapstructure:"interval"`    // How often the leader compacts the history
+	BatchSize  int           `mapstructure:"batch_size"`  // Records deleted per request
+	ArchiveDir string        `mapstructure:"archive_dir"` // Pruned records are appended here if set
 }
 
 // Load loads configuration from file and environment variables.
@@ -22,6 +97,22 @@ func Load() (*Config, error) {
 	viper.SetDefault("etcd_timeout", "5s")
 	viper.SetDefault("http_listen_addr", ":8080")
 	viper.SetDefault("leader_election_ttl", "10s")
+	viper.SetDefault("log_dir", "./data/logs")
+	viper.SetDefault("log_max_age", "168h")
+	viper.SetDefault("output_limit_bytes", 64*1024)
+	viper.SetDefault("retention.max_count", 0)
+	viper.SetDefault("retention.interval", "10m")
+	viper.SetDefault("retention.batch_size", 100)
+	viper.SetDefault("storage.backend", StorageBackendEtcd)
+	viper.SetDefault("storage.sqlite_path", "./data/cron.db")
+	viper.SetDefault("storage.job_poll_interval", "1s")
+	viper.SetDefault("dispatch.strategy", "least_loaded")
+	viper.SetDefault("dispatch.timeout", "10s")
+	viper.SetDefault("worker.heartbeat_interval", "1s")
+	viper.SetDefault("health.probe_interval", "5s")
+	viper.SetDefault("health.probe_timeout", "2s")
+	viper.SetDefault("health.failure_threshold", 3)
+	viper.SetDefault("health.cooldown", "30s")
 
 	// Set config file details
 	viper.SetConfigName("config")    // name of config file (without extension)
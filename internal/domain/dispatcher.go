@@ -1,9 +1,46 @@
 // internal/domain/dispatcher.go
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrWorkerNotFound is returned when a worker is not (or no longer) registered.
+var ErrWorkerNotFound = errors.New("worker not found")
+
+// ErrTaskRejected is returned when a worker refuses a job it cannot run, e.g. because
+// it is not permitted to switch to the job's user.
+var ErrTaskRejected = errors.New("task rejected by worker")
+
+// ErrNoCandidateWorker is returned when a dispatch has no worker to try before its first attempt.
+var ErrNoCandidateWorker = errors.New("no candidate worker")
+
+// DispatchOptions carries per-dispatch settings that are not part of the job definition.
+type DispatchOptions struct {
+	Trigger TriggerType // What caused this dispatch; empty means TriggerSchedule
+
+	// The fields below continue an existing execution on another worker.
+	ExecutionID      string   // Execution to continue; empty starts a new execution
+	Attempt          int      // Number of the attempt being dispatched
+	ExcludeWorkerIDs []string // Workers to avoid, unless no other worker is available
+}
 
 // Dispatcher defines the interface for dispatching jobs to workers.
 type Dispatcher interface {
-	DispatchTask(ctx context.Context, job *Job) error
+	// DispatchTask sends the job to a worker and returns the ID of the execution it started.
+	DispatchTask(ctx context.Context, job *Job, opts DispatchOptions) (string, error)
+}
+
+// ExecutionController controls executions that are already running on a worker.
+type ExecutionController interface {
+	// CancelExecution asks the worker running the execution to stop it.
+	CancelExecution(ctx context.Context, workerID, executionID string) error
+	// StreamExecutionLogs copies the output of a running execution to w. With follow set,
+	// it keeps copying until the execution ends or ctx is cancelled.
+	StreamExecutionLogs(ctx context.Context, workerID, executionID string, follow bool, w io.Writer) error
+	// GetExecutionOutput copies the full output of an execution from the log store of
+	// the worker that ran it to w. It returns ErrLogNotFound if the worker has none.
+	GetExecutionOutput(ctx context.Context, workerID, jobName, executionID string, w io.Writer) error
 }
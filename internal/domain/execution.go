@@ -3,30 +3,129 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 )
 
+// ErrExecutionNotFound is returned when no record exists for an execution.
+var ErrExecutionNotFound = errors.New("execution not found")
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrExecutionNotRunning is returned when an operation requires a running execution.
+var ErrExecutionNotRunning = errors.New("execution is not running")
+
+// ErrExecutionCancelled is the cancellation cause of an execution stopped on request.
+var ErrExecutionCancelled = errors.New("execution cancelled")
+
+// ErrExecutionTimedOut is the cancellation cause of an execution that exceeded its job's timeout.
+var ErrExecutionTimedOut = errors.New("execution timed out")
+
+// ErrNonRetriable marks an executor error that retrying cannot fix, e.g. an HTTP 4xx response.
+var ErrNonRetriable = errors.New("non-retriable error")
+
+// ErrExecutionConflict is returned by Save when the stored record was changed since it was read.
+var ErrExecutionConflict = errors.New("execution record changed concurrently")
+
 // ExecutionStatus defines the status of a job execution.
 type ExecutionStatus string
 
 const (
-	ExecutionStatusRunning ExecutionStatus = "running"
-	ExecutionStatusSuccess ExecutionStatus = "success"
-	ExecutionStatusFailed  ExecutionStatus = "failed"
+	ExecutionStatusRunning   ExecutionStatus = "running"
+	ExecutionStatusSuccess   ExecutionStatus = "success"
+	ExecutionStatusFailed    ExecutionStatus = "failed"
+	ExecutionStatusCancelled ExecutionStatus = "cancelled"
+	ExecutionStatusTimedOut  ExecutionStatus = "timed_out"
+	// ExecutionStatusRetryPending means an attempt failed and the next one waits to be dispatched to another worker.
+	ExecutionStatusRetryPending ExecutionStatus = "retry_pending"
+)
+
+// IsValid reports whether s is one of the known execution statuses.
+func (s ExecutionStatus) IsValid() bool {
+	switch s {
+	case ExecutionStatusRunning, ExecutionStatusSuccess, ExecutionStatusFailed, ExecutionStatusCancelled,
+		ExecutionStatusTimedOut, ExecutionStatusRetryPending:
+		return true
+	}
+	return false
+}
+
+// TriggerType records what caused an execution to be dispatched.
+type TriggerType string
+
+const (
+	TriggerSchedule TriggerType = "schedule" // Fired by the cron scheduler
+	TriggerMisfire  TriggerType = "misfire"  // Caught up after a leader failover
+	TriggerManual   TriggerType = "manual"   // Requested through the API
+	TriggerRetry    TriggerType = "retry"    // Another attempt of a failed execution on a different worker
 )
 
+// FailureReason classifies why an execution failed where the status alone does not tell.
+type FailureReason string
+
+const (
+	FailureReasonOOMKilled FailureReason = "oom_killed" // Killed for exceeding the job's memory limit
+)
+
+// ExecutionAttempt records a single attempt of an execution.
+type ExecutionAttempt struct {
+	Attempt   int       `json:"attempt"` // 1 for the first attempt
+	WorkerID  string    `json:"worker_id"`
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+	Error     string    `json:"error,omitempty"`
+}
+
 // ExecutionRecord represents a single execution instance of a job.
 type ExecutionRecord struct {
-	ID              string          `json:"id"`                // Unique ID for this specific execution attempt
-	JobName         string          `json:"job_name"`          // Name of the job being executed
-	StartTime       time.Time       `json:"start_time"`        // When the execution started
-	EndTime         time.Time       `json:"end_time"`          // When the execution ended
-	Status          ExecutionStatus `json:"status"`            // Status: running, success, failed
-	Output          string          `json:"output,omitempty"`  // Standard output (e.g., for shell commands)
-	Error           string          `json:"error,omitempty"`   // Error message if execution failed
-	RetriesAttempted int            `json:"retries_attempted"` // Number of retries attempted for this execution instance
-	WorkerID        string          `json:"worker_id,omitempty"` // ID of the worker that executed the job
+	ID               string             `json:"id"`                  // Unique ID of this execution, shared by all of its attempts
+	JobName          string             `json:"job_name"`            // Name of the job being executed
+	StartTime        time.Time          `json:"start_time"`          // When the execution started
+	EndTime          time.Time          `json:"end_time"`            // When the execution ended
+	Status           ExecutionStatus    `json:"status"`              // Status: running, success, failed, cancelled, timed_out, retry_pending
+	Output           string             `json:"output,omitempty"`    // Standard output (e.g., for shell commands)
+	Error            string             `json:"error,omitempty"`     // Error message if execution failed
+	RetriesAttempted int                `json:"retries_attempted"`   // Number of retries attempted for this execution instance
+	WorkerID         string             `json:"worker_id,omitempty"` // ID of the worker that ran the latest attempt
+	Trigger          TriggerType        `json:"trigger,omitempty"`   // What caused this execution: schedule, misfire, manual or retry
+	Attempts         []ExecutionAttempt `json:"attempts,omitempty"`  // Every attempt made, across workers
+	ExitCode         *int               `json:"exit_code,omitempty"` // Exit code of the last attempt's process; -1 if ended by a signal
+	Signal           string             `json:"signal,omitempty"`    // Signal that ended the last attempt's process, e.g. SIGTERM
+	FailureReason    FailureReason      `json:"failure_reason,omitempty"`
+
+	// Resource usage of the last attempt.
+	DurationMs  int64 `json:"duration_ms"`             // Run time of the last attempt
+	UserCPUMs   int64 `json:"user_cpu_ms,omitempty"`   // User CPU time of the process
+	SystemCPUMs int64 `json:"system_cpu_ms,omitempty"` // System CPU time of the process
+	MaxRSSBytes int64 `json:"max_rss_bytes,omitempty"` // Peak resident set size of the process
+	OutputBytes int64 `json:"output_bytes"`            // Bytes of output produced, including any that was not kept in Output
+	HTTPStatus  int   `json:"http_status,omitempty"`   // Status code of the HTTP response
+
+	// Revision is the storage revision the record was read at or last saved with; 0 for a
+	// record that was never saved. Save only overwrites the stored record at that revision,
+	// so an attempt that has been handed on cannot overwrite the record of a later one.
+	Revision int64 `json:"-"`
+}
+
+// ApplyResult replaces the exit status and resource usage with those of an attempt that ran for duration.
+func (r *ExecutionRecord) ApplyResult(result *ExecutionResult, duration time.Duration) {
+	if result == nil {
+		result = &ExecutionResult{}
+	}
+	r.ExitCode = result.ExitCode
+	r.Signal = result.Signal
+	r.DurationMs = duration.Milliseconds()
+	r.UserCPUMs = result.UserCPU.Milliseconds()
+	r.SystemCPUMs = result.SystemCPU.Milliseconds()
+	r.MaxRSSBytes = result.MaxRSS
+	r.OutputBytes = result.OutputBytes
+	r.HTTPStatus = result.HTTPStatus
+	r.FailureReason = ""
+	if result.OOMKilled {
+		r.FailureReason = FailureReasonOOMKilled
+	}
 }
 
 // Validate checks if the execution record is valid.
@@ -46,14 +145,45 @@ func (r *ExecutionRecord) Validate() error {
 	return nil
 }
 
-
 // ExecutionRepository defines the interface for persisting and retrieving execution records.
 type ExecutionRepository interface {
-	// Save persists a single execution record.
+	// Save persists a single execution record and updates its Revision. It returns
+	// ErrExecutionConflict if the stored record is not at the record's Revision.
 	Save(ctx context.Context, record *ExecutionRecord) error
-	// ListByJobName retrieves historical execution records for a specific job, with pagination.
-	// Records should typically be returned in reverse chronological order (newest first).
-	ListByJobName(ctx context.Context, jobName string, page, pageSize int) ([]*ExecutionRecord, error)
+	// ListByJobName retrieves up to pageSize execution records of a job, newest first,
+	// starting after cursor ("" for the first page). It also returns the cursor of the
+	// next page, which is "" when there are no more records.
+	ListByJobName(ctx context.Context, jobName, cursor string, pageSize int) ([]*ExecutionRecord, string, error)
 	// Get retrieves a single execution record by its JobName and ExecutionID.
 	Get(ctx context.Context, jobName, executionID string) (*ExecutionRecord, error)
+	// ListExpired returns up to limit records of a job, newest first, that are not among
+	// its keep newest records or that did not start after before, starting after cursor
+	// ("" for the first batch). A keep of 0 or a zero before disables that bound. It also
+	// returns the cursor of the next batch, which is "" when there are no more records.
+	ListExpired(ctx context.Context, jobName string, keep int, before time.Time, cursor string, limit int) ([]*ExecutionRecord, string, error)
+	// Delete removes execution records of a job and returns how many existed.
+	Delete(ctx context.Context, jobName string, executionIDs []string) (int, error)
+	// ListJobNames returns the names of all jobs that have execution records, including
+	// jobs that have since been deleted.
+	ListJobNames(ctx context.Context) ([]string, error)
+	// Query retrieves up to q.Limit records across jobs that match q, newest first. It
+	// also returns the cursor of the next page, which is "" when there are no more records.
+	// A page may hold fewer records than the limit even if more follow.
+	Query(ctx context.Context, q ExecutionQuery) ([]*ExecutionRecord, string, error)
+}
+
+// ExecutionQuery selects execution records. Empty fields do not filter.
+type ExecutionQuery struct {
+	JobName  string
+	Status   ExecutionStatus
+	WorkerID string
+	Since    time.Time // Records that started at or after Since
+	Until    time.Time // Records that started at or before Until
+	Cursor   string    // From the previous page of the same query
+	Limit    int
+}
+
+// ExecutionArchive keeps execution records that are pruned from the history.
+type ExecutionArchive interface {
+	Archive(ctx context.Context, records []*ExecutionRecord) error
 }
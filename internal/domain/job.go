@@ -2,6 +2,10 @@ package domain
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -15,15 +19,95 @@ const (
 
 // JobExecutor represents the action to be performed when a job triggers.
 type JobExecutor struct {
-	URL     string `json:"url,omitempty"`    // For HTTP executor
-	Method  string `json:"method,omitempty"` // For HTTP executor
-	Command string `json:"command,omitempty"`// For Shell executor
+	URL            string             `json:"url,omitempty"`             // For HTTP executor
+	Method         string             `json:"method,omitempty"`          // For HTTP executor
+	Headers        map[string]string  `json:"headers,omitempty"`         // For HTTP executor
+	Body           string             `json:"body,omitempty"`            // For HTTP executor, sent as is
+	Auth           *HTTPAuth          `json:"auth,omitempty"`            // For HTTP executor
+	ExpectedStatus []int              `json:"expected_status,omitempty"` // For HTTP executor; empty means any 2xx or 3xx
+	Assertion      *ResponseAssertion `json:"assertion,omitempty"`       // For HTTP executor, checked against the response body
+	Command        string             `json:"command,omitempty"`         // For Shell executor
+	Env            map[string]string  `json:"env,omitempty"`             // For Shell executor, added to the worker's environment
+	WorkingDir     string             `json:"working_dir,omitempty"`     // For Shell executor; empty means the worker's working directory
+	User           string             `json:"user,omitempty"`            // For Shell executor, user name or uid to run as
+	Group          string             `json:"group,omitempty"`           // For Shell executor, group name or gid to run as
+	// KillGracePeriod is how long a shell job may take to exit after SIGTERM before its
+	// process group is killed; 0 means DefaultKillGracePeriod.
+	KillGracePeriod time.Duration `json:"kill_grace_period,omitempty"`
+	// Limits bounds the resources of a shell job; workers enforce it with a cgroup v2 per execution.
+	Limits *ResourceLimits `json:"limits,omitempty"`
+}
+
+// ResourceLimits bounds the resources a shell job may use on a worker. Zero values are not limited.
+type ResourceLimits struct {
+	CPU      float64 `json:"cpu,omitempty"`       // CPU cores, e.g. 0.5
+	MemoryMB int64   `json:"memory_mb,omitempty"` // Memory, in MiB; exceeding it gets the job OOM-killed
+	Pids     int64   `json:"pids,omitempty"`      // Number of processes and threads
+}
+
+// HTTPAuthType defines how an HTTP job authenticates.
+type HTTPAuthType string
+
+const (
+	HTTPAuthBasic  HTTPAuthType = "basic"
+	HTTPAuthBearer HTTPAuthType = "bearer"
+)
+
+// HTTPAuth holds the credentials of an HTTP job.
+type HTTPAuth struct {
+	Type     HTTPAuthType `json:"type"`
+	Username string       `json:"username,omitempty"` // For basic auth
+	Password string       `json:"password,omitempty"` // For basic auth
+	Token    string       `json:"token,omitempty"`    // For bearer auth
 }
 
 // RetryPolicy defines the retry strategy for a job upon failure.
 type RetryPolicy struct {
 	MaxRetries int           `json:"max_retries"`
-	Backoff    time.Duration `json:"backoff"`
+	Backoff    time.Duration `json:"backoff"`               // Delay before the first retry
+	Multiplier float64       `json:"multiplier,omitempty"`  // Growth factor of the delay per retry; <= 1 keeps it constant
+	MaxBackoff time.Duration `json:"max_backoff,omitempty"` // Upper bound of the delay; 0 means unbounded
+	Jitter     float64       `json:"jitter,omitempty"`      // Fraction of the delay that is randomized, between 0 and 1
+	// RetryOnDifferentWorker hands each retry back to the master, which dispatches it
+	// to a worker that has not yet attempted this execution.
+	RetryOnDifferentWorker bool `json:"retry_on_different_worker,omitempty"`
+}
+
+// Delay returns how long to wait before the given retry (1 for the first retry).
+func (p *RetryPolicy) Delay(retry int) time.Duration {
+	delay := float64(p.Backoff)
+	if p.Multiplier > 1 {
+		delay *= math.Pow(p.Multiplier, float64(retry-1))
+	}
+	if p.MaxBackoff > 0 && delay > float64(p.MaxBackoff) {
+		delay = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		delay -= delay * p.Jitter * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// RetentionPolicy bounds how much execution history of a job is kept. Zero values fall
+// back to the global retention settings.
+type RetentionPolicy struct {
+	MaxCount int           `json:"max_count,omitempty"` // Number of newest records to keep
+	MaxAge   time.Duration `json:"max_age,omitempty"`   // Records that started longer ago are deleted
+}
+
+// Merge returns p with its zero values taken from defaults.
+func (p *RetentionPolicy) Merge(defaults RetentionPolicy) RetentionPolicy {
+	if p == nil {
+		return defaults
+	}
+	merged := *p
+	if merged.MaxCount == 0 {
+		merged.MaxCount = defaults.MaxCount
+	}
+	if merged.MaxAge == 0 {
+		merged.MaxAge = defaults.MaxAge
+	}
+	return merged
 }
 
 // ConcurrencyPolicy defines how concurrent executions of the same job are handled.
@@ -34,6 +118,25 @@ const (
 	ConcurrencyPolicyForbid ConcurrencyPolicy = "Forbid"
 )
 
+// MisfirePolicy defines how runs missed while no leader was scheduling are handled.
+type MisfirePolicy string
+
+const (
+	MisfirePolicySkip     MisfirePolicy = "Skip"     // Drop missed runs and wait for the next fire time
+	MisfirePolicyFireOnce MisfirePolicy = "FireOnce" // Dispatch a single run for all missed fire times
+	MisfirePolicyFireAll  MisfirePolicy = "FireAll"  // Dispatch every missed run, up to MaxMisfireRuns
+)
+
+// DefaultMaxMisfireRuns caps FireAll catch-up when a job does not set MaxMisfireRuns.
+const DefaultMaxMisfireRuns = 10
+
+// DefaultJobTimeout bounds an execution when the job does not set its own Timeout.
+const DefaultJobTimeout = 30 * time.Second
+
+// DefaultKillGracePeriod is how long a cancelled or timed out shell job may take to exit
+// after SIGTERM when the job does not set its own KillGracePeriod.
+const DefaultKillGracePeriod = 5 * time.Second
+
 // Job represents a scheduled task in the distributed cron system.
 type Job struct {
 	ID                string            `json:"id"`
@@ -43,8 +146,19 @@ type Job struct {
 	Executor          JobExecutor       `json:"executor"`
 	ConcurrencyPolicy ConcurrencyPolicy `json:"concurrency_policy,omitempty"`
 	RetryPolicy       *RetryPolicy      `json:"retry_policy,omitempty"`
+	TimeZone          string            `json:"time_zone,omitempty"` // IANA zone the schedule is evaluated in; empty means the master's local time
+	MisfirePolicy     MisfirePolicy     `json:"misfire_policy,omitempty"`
+	MaxMisfireRuns    int               `json:"max_misfire_runs,omitempty"` // Cap for FireAll; 0 means DefaultMaxMisfireRuns
+	Paused            bool              `json:"paused"`                     // Paused jobs are kept but not scheduled
+	Timeout           time.Duration     `json:"timeout,omitempty"`          // Maximum run time of one execution; 0 means DefaultJobTimeout
+	Retention         *RetentionPolicy  `json:"retention,omitempty"`        // Overrides the global history retention
 	CreatedAt         time.Time         `json:"created_at"`
 	UpdatedAt         time.Time         `json:"updated_at"`
+
+	// NodeSelector lists labels a worker must have, with these values, to run the job.
+	NodeSelector map[string]string `json:"node_selector,omitempty"`
+	// Affinity lists further rules the labels of a worker must satisfy to run the job.
+	Affinity []LabelRequirement `json:"affinity,omitempty"`
 }
 
 // Validate checks if the job definition is valid.
@@ -63,16 +177,84 @@ func (j *Job) Validate() error {
 		if j.Executor.Method == "" {
 			j.Executor.Method = "GET"
 		}
+		if err := j.Executor.validateHTTP(); err != nil {
+			return err
+		}
 	case ExecutorTypeShell:
 		if j.Executor.Command == "" {
 			return fmt.Errorf("executor command cannot be empty for shell job")
 		}
+		for k := range j.Executor.Env {
+			if k == "" || strings.Contains(k, "=") {
+				return fmt.Errorf("invalid environment variable name %q", k)
+			}
+		}
+		if j.Executor.WorkingDir != "" && !filepath.IsAbs(j.Executor.WorkingDir) {
+			return fmt.Errorf("working directory must be an absolute path")
+		}
+		if j.Executor.KillGracePeriod < 0 {
+			return fmt.Errorf("kill grace period cannot be negative")
+		}
+		if l := j.Executor.Limits; l != nil {
+			if l.CPU < 0 || l.MemoryMB < 0 || l.Pids < 0 {
+				return fmt.Errorf("resource limits cannot be negative")
+			}
+			if *l == (ResourceLimits{}) {
+				j.Executor.Limits = nil
+			}
+		}
 	default:
 		return fmt.Errorf("invalid executor type: %s", j.ExecutorType)
 	}
 
+	if j.TimeZone != "" {
+		if _, err := time.LoadLocation(j.TimeZone); err != nil {
+			return fmt.Errorf("invalid time zone %q: %w", j.TimeZone, err)
+		}
+	}
+
 	if j.ConcurrencyPolicy == "" {
 		j.ConcurrencyPolicy = ConcurrencyPolicyAllow
 	}
+
+	switch j.MisfirePolicy {
+	case "":
+		j.MisfirePolicy = MisfirePolicySkip
+	case MisfirePolicySkip, MisfirePolicyFireOnce, MisfirePolicyFireAll:
+	default:
+		return fmt.Errorf("invalid misfire policy: %s", j.MisfirePolicy)
+	}
+	if j.MaxMisfireRuns < 0 {
+		return fmt.Errorf("max misfire runs cannot be negative")
+	}
+	if j.Timeout < 0 {
+		return fmt.Errorf("timeout cannot be negative")
+	}
+	if p := j.RetryPolicy; p != nil {
+		if p.MaxRetries < 0 || p.Backoff < 0 || p.MaxBackoff < 0 {
+			return fmt.Errorf("retry policy values cannot be negative")
+		}
+		if p.Jitter < 0 || p.Jitter > 1 {
+			return fmt.Errorf("retry jitter must be between 0 and 1")
+		}
+	}
+	if r := j.Retention; r != nil {
+		if r.MaxCount < 0 || r.MaxAge < 0 {
+			return fmt.Errorf("retention values cannot be negative")
+		}
+		if *r == (RetentionPolicy{}) {
+			j.Retention = nil
+		}
+	}
+	for k := range j.NodeSelector {
+		if k == "" {
+			return fmt.Errorf("node selector label name cannot be empty")
+		}
+	}
+	for _, r := range j.Affinity {
+		if err := r.validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
\ No newline at end of file
@@ -16,3 +16,30 @@ type JobRepository interface {
 	Get(ctx context.Context, name string) (*Job, error)
 	List(ctx context.Context) ([]*Job, error)
 }
+
+// JobEventType describes a change to a stored job definition.
+type JobEventType string
+
+const (
+	JobEventPut    JobEventType = "put"    // A job was created or updated
+	JobEventDelete JobEventType = "delete" // A job was deleted
+	JobEventSync   JobEventType = "sync"   // The full set of stored jobs, replacing whatever was known before
+)
+
+// JobEvent is a single change observed by a JobWatcher.
+type JobEvent struct {
+	Type JobEventType
+	Name string
+	Job  *Job   // The new definition; nil for JobEventDelete
+	Jobs []*Job // Every stored job; only set for JobEventSync
+}
+
+// JobWatcher streams changes to stored job definitions, so the leader can keep
+// its scheduler in sync with writes accepted by any master.
+type JobWatcher interface {
+	// Watch first emits a JobEventSync with every stored job, then an event for every
+	// job put or deleted after that snapshot. Whenever the watch has to be re-established,
+	// e.g. after a compaction or a lost connection, it emits a new JobEventSync before
+	// further changes. The channel is closed once ctx is cancelled.
+	Watch(ctx context.Context) <-chan JobEvent
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		want   []time.Duration // Delays of retries 1, 2, ...
+	}{
+		{
+			name:   "constant without a multiplier",
+			policy: RetryPolicy{Backoff: time.Second},
+			want:   []time.Duration{time.Second, time.Second, time.Second},
+		},
+		{
+			name:   "a multiplier of 1 or less keeps the delay constant",
+			policy: RetryPolicy{Backoff: time.Second, Multiplier: 0.5},
+			want:   []time.Duration{time.Second, time.Second, time.Second},
+		},
+		{
+			name:   "exponential growth",
+			policy: RetryPolicy{Backoff: time.Second, Multiplier: 2},
+			want:   []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second},
+		},
+		{
+			name:   "capped by max backoff",
+			policy: RetryPolicy{Backoff: time.Second, Multiplier: 3, MaxBackoff: 5 * time.Second},
+			want:   []time.Duration{time.Second, 3 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+		{
+			name:   "no backoff",
+			policy: RetryPolicy{Multiplier: 2},
+			want:   []time.Duration{0, 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i, want := range tt.want {
+				if got := tt.policy.Delay(i + 1); got != want {
+					t.Errorf("Delay(%d) = %v, want %v", i+1, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDelayJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   RetryPolicy
+		retry    int
+		min, max time.Duration
+	}{
+		{
+			name:   "half of the delay is randomized",
+			policy: RetryPolicy{Backoff: 4 * time.Second, Multiplier: 2, Jitter: 0.5},
+			retry:  2,
+			min:    4 * time.Second,
+			max:    8 * time.Second,
+		},
+		{
+			name:   "jitter applies after the cap",
+			policy: RetryPolicy{Backoff: time.Second, Multiplier: 10, MaxBackoff: 10 * time.Second, Jitter: 0.2},
+			retry:  3,
+			min:    8 * time.Second,
+			max:    10 * time.Second,
+		},
+		{
+			name:   "full jitter",
+			policy: RetryPolicy{Backoff: time.Second, Jitter: 1},
+			retry:  1,
+			min:    0,
+			max:    time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for range 1000 {
+				if got := tt.policy.Delay(tt.retry); got < tt.min || got > tt.max {
+					t.Fatalf("Delay(%d) = %v, want between %v and %v", tt.retry, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
@@ -1,9 +1,37 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrNotLeader is returned by operations only the leader may perform when they reach a follower.
+var ErrNotLeader = errors.New("this master is not the leader")
 
 type LeaderElectionManager interface {
 	Campaign(ctx context.Context) (<-chan struct{}, error)
 	Resign(ctx context.Context) error
 	IsLeader() bool
 }
+
+// ClusterMember is a master taking part in the leader election.
+type ClusterMember struct {
+	NodeID          string `json:"node_id"`
+	APIAddr         string `json:"api_addr,omitempty"`   // Where other masters reach its HTTP API
+	StorageID       string `json:"storage_id,omitempty"` // ID of its SQLite database; empty with etcd storage
+	Leader          bool   `json:"leader"`
+	LeaseTTLSeconds int64  `json:"lease_ttl_seconds"` // Time left before the candidacy expires unless renewed; zero if unknown
+}
+
+// ClusterStatus describes the masters of the cluster.
+type ClusterStatus struct {
+	NodeID     string          `json:"node_id"`          // The master that answered
+	Leader     string          `json:"leader,omitempty"` // Node ID of the leader; empty while there is none
+	Candidates []ClusterMember `json:"candidates"`       // In election order, the leader first
+}
+
+// ClusterObserver reads the state of the leader election.
+type ClusterObserver interface {
+	// Candidates returns the masters campaigning for leadership, the leader first.
+	Candidates(ctx context.Context) ([]ClusterMember, error)
+}
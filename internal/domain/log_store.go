@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// ErrLogNotFound is returned when the full output of an execution is not in the log store.
+var ErrLogNotFound = errors.New("execution output not found")
+
+// LogStore keeps the full output of executions, which can be much larger than the
+// truncated ExecutionRecord.Output.
+type LogStore interface {
+	// Create returns a writer for the output of an execution. Writing to the same
+	// execution again, e.g. for a retry, appends to the existing output.
+	Create(ctx context.Context, jobName, executionID string) (io.WriteCloser, error)
+	// Open returns the full output of an execution, or ErrLogNotFound.
+	Open(ctx context.Context, jobName, executionID string) (io.ReadCloser, error)
+	// Prune removes the output of executions last written before before and returns
+	// how many were removed.
+	Prune(ctx context.Context, before time.Time) (int, error)
+}
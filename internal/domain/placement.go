@@ -0,0 +1,81 @@
+// internal/domain/placement.go
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrNoEligibleWorker is returned when no registered worker satisfies a job's node
+// selector and affinity rules.
+var ErrNoEligibleWorker = errors.New("no eligible worker")
+
+// LabelOperator defines how a LabelRequirement compares a worker label.
+type LabelOperator string
+
+const (
+	LabelOpIn           LabelOperator = "In"           // The label is set to one of Values
+	LabelOpNotIn        LabelOperator = "NotIn"        // The label is unset or set to none of Values
+	LabelOpExists       LabelOperator = "Exists"       // The label is set, to any value
+	LabelOpDoesNotExist LabelOperator = "DoesNotExist" // The label is unset
+)
+
+// LabelRequirement is an affinity rule a worker's labels must satisfy to run a job.
+type LabelRequirement struct {
+	Key      string        `json:"key"`
+	Operator LabelOperator `json:"operator"`
+	Values   []string      `json:"values,omitempty"` // For In and NotIn
+}
+
+// Matches reports whether labels satisfy the requirement.
+func (r LabelRequirement) Matches(labels map[string]string) bool {
+	value, ok := labels[r.Key]
+	switch r.Operator {
+	case LabelOpIn:
+		return ok && slices.Contains(r.Values, value)
+	case LabelOpNotIn:
+		return !ok || !slices.Contains(r.Values, value)
+	case LabelOpExists:
+		return ok
+	case LabelOpDoesNotExist:
+		return !ok
+	default:
+		return false
+	}
+}
+
+func (r LabelRequirement) validate() error {
+	if r.Key == "" {
+		return fmt.Errorf("affinity rule key cannot be empty")
+	}
+	switch r.Operator {
+	case LabelOpIn, LabelOpNotIn:
+		if len(r.Values) == 0 {
+			return fmt.Errorf("affinity rule %s %s needs at least one value", r.Key, r.Operator)
+		}
+	case LabelOpExists, LabelOpDoesNotExist:
+		if len(r.Values) > 0 {
+			return fmt.Errorf("affinity rule %s %s takes no values", r.Key, r.Operator)
+		}
+	default:
+		return fmt.Errorf("invalid affinity operator: %s", r.Operator)
+	}
+	return nil
+}
+
+// AcceptsWorker reports whether a worker with the given labels may run the job: it must
+// have every label of the node selector and satisfy every affinity rule.
+func (j *Job) AcceptsWorker(labels map[string]string) bool {
+	for k, v := range j.NodeSelector {
+		if value, ok := labels[k]; !ok || value != v {
+			return false
+		}
+	}
+	for _, r := range j.Affinity {
+		if !r.Matches(labels) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,119 @@
+package domain
+
+import "testing"
+
+func TestLabelRequirementMatches(t *testing.T) {
+	labels := map[string]string{"zone": "dmz", "gpu": "", "arch": "amd64"}
+
+	tests := []struct {
+		name        string
+		requirement LabelRequirement
+		want        bool
+	}{
+		{name: "In with the value", requirement: LabelRequirement{Key: "zone", Operator: LabelOpIn, Values: []string{"core", "dmz"}}, want: true},
+		{name: "In without the value", requirement: LabelRequirement{Key: "zone", Operator: LabelOpIn, Values: []string{"core"}}, want: false},
+		{name: "In on a missing label", requirement: LabelRequirement{Key: "rack", Operator: LabelOpIn, Values: []string{""}}, want: false},
+		{name: "In with an empty value", requirement: LabelRequirement{Key: "gpu", Operator: LabelOpIn, Values: []string{""}}, want: true},
+		{name: "NotIn without the value", requirement: LabelRequirement{Key: "arch", Operator: LabelOpNotIn, Values: []string{"arm64"}}, want: true},
+		{name: "NotIn with the value", requirement: LabelRequirement{Key: "arch", Operator: LabelOpNotIn, Values: []string{"amd64"}}, want: false},
+		{name: "NotIn on a missing label", requirement: LabelRequirement{Key: "rack", Operator: LabelOpNotIn, Values: []string{"r1"}}, want: true},
+		{name: "Exists", requirement: LabelRequirement{Key: "gpu", Operator: LabelOpExists}, want: true},
+		{name: "Exists on a missing label", requirement: LabelRequirement{Key: "rack", Operator: LabelOpExists}, want: false},
+		{name: "DoesNotExist", requirement: LabelRequirement{Key: "rack", Operator: LabelOpDoesNotExist}, want: true},
+		{name: "DoesNotExist on a set label", requirement: LabelRequirement{Key: "gpu", Operator: LabelOpDoesNotExist}, want: false},
+		{name: "unknown operator", requirement: LabelRequirement{Key: "zone", Operator: "Gt", Values: []string{"a"}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.requirement.Matches(labels); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLabelRequirementValidate(t *testing.T) {
+	tests := []struct {
+		name        string
+		requirement LabelRequirement
+		wantErr     bool
+	}{
+		{name: "In", requirement: LabelRequirement{Key: "zone", Operator: LabelOpIn, Values: []string{"dmz"}}},
+		{name: "Exists", requirement: LabelRequirement{Key: "zone", Operator: LabelOpExists}},
+		{name: "empty key", requirement: LabelRequirement{Operator: LabelOpExists}, wantErr: true},
+		{name: "In without values", requirement: LabelRequirement{Key: "zone", Operator: LabelOpIn}, wantErr: true},
+		{name: "NotIn without values", requirement: LabelRequirement{Key: "zone", Operator: LabelOpNotIn}, wantErr: true},
+		{name: "Exists with values", requirement: LabelRequirement{Key: "zone", Operator: LabelOpExists, Values: []string{"dmz"}}, wantErr: true},
+		{name: "DoesNotExist with values", requirement: LabelRequirement{Key: "zone", Operator: LabelOpDoesNotExist, Values: []string{"dmz"}}, wantErr: true},
+		{name: "unknown operator", requirement: LabelRequirement{Key: "zone", Operator: "Gt"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.requirement.validate(); (err != nil) != tt.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestJobAcceptsWorker(t *testing.T) {
+	tests := []struct {
+		name   string
+		job    Job
+		labels map[string]string
+		want   bool
+	}{
+		{name: "no constraints accept any worker", job: Job{}, labels: nil, want: true},
+		{
+			name:   "node selector matches",
+			job:    Job{NodeSelector: map[string]string{"zone": "dmz", "arch": "amd64"}},
+			labels: map[string]string{"zone": "dmz", "arch": "amd64", "gpu": "a100"},
+			want:   true,
+		},
+		{
+			name:   "node selector value differs",
+			job:    Job{NodeSelector: map[string]string{"zone": "dmz"}},
+			labels: map[string]string{"zone": "core"},
+			want:   false,
+		},
+		{
+			name:   "node selector label missing",
+			job:    Job{NodeSelector: map[string]string{"zone": ""}},
+			labels: map[string]string{},
+			want:   false,
+		},
+		{
+			name: "node selector and every affinity rule hold",
+			job: Job{
+				NodeSelector: map[string]string{"zone": "dmz"},
+				Affinity: []LabelRequirement{
+					{Key: "arch", Operator: LabelOpIn, Values: []string{"amd64", "arm64"}},
+					{Key: "maintenance", Operator: LabelOpDoesNotExist},
+				},
+			},
+			labels: map[string]string{"zone": "dmz", "arch": "arm64"},
+			want:   true,
+		},
+		{
+			name: "one affinity rule fails",
+			job: Job{
+				Affinity: []LabelRequirement{
+					{Key: "arch", Operator: LabelOpIn, Values: []string{"amd64"}},
+					{Key: "maintenance", Operator: LabelOpDoesNotExist},
+				},
+			},
+			labels: map[string]string{"arch": "amd64", "maintenance": "true"},
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.job.AcceptsWorker(tt.labels); got != tt.want {
+				t.Errorf("AcceptsWorker() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
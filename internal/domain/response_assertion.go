@@ -0,0 +1,203 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AssertionType defines how a response assertion matches the response body.
+type AssertionType string
+
+const (
+	AssertionSubstring AssertionType = "substring" // The body contains Expression
+	AssertionRegex     AssertionType = "regex"     // The body matches the regular expression Expression
+	AssertionJSONPath  AssertionType = "jsonpath"  // The JSONPath Expression exists in the body, and equals Value if set
+)
+
+// ResponseAssertion decides whether an HTTP response counts as a success.
+type ResponseAssertion struct {
+	Type       AssertionType `json:"type"`
+	Expression string        `json:"expression"`
+	Value      string        `json:"value,omitempty"` // For jsonpath: strings compare as is, other values as JSON
+}
+
+// validateHTTP checks the HTTP specific settings of an executor.
+func (e *JobExecutor) validateHTTP() error {
+	if a := e.Auth; a != nil {
+		switch a.Type {
+		case HTTPAuthBasic:
+			if a.Username == "" {
+				return fmt.Errorf("basic auth requires a username")
+			}
+		case HTTPAuthBearer:
+			if a.Token == "" {
+				return fmt.Errorf("bearer auth requires a token")
+			}
+		default:
+			return fmt.Errorf("invalid auth type: %s", a.Type)
+		}
+	}
+	for _, code := range e.ExpectedStatus {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("invalid expected status code: %d", code)
+		}
+	}
+	if e.Assertion != nil {
+		return e.Assertion.Validate()
+	}
+	return nil
+}
+
+// Validate checks that the assertion's expression can be evaluated.
+func (a *ResponseAssertion) Validate() error {
+	if a.Expression == "" {
+		return fmt.Errorf("assertion expression cannot be empty")
+	}
+	switch a.Type {
+	case AssertionSubstring:
+	case AssertionRegex:
+		if _, err := regexp.Compile(a.Expression); err != nil {
+			return fmt.Errorf("invalid assertion regex: %w", err)
+		}
+	case AssertionJSONPath:
+		if _, err := parseJSONPath(a.Expression); err != nil {
+			return fmt.Errorf("invalid assertion JSONPath: %w", err)
+		}
+	default:
+		return fmt.Errorf("invalid assertion type: %s", a.Type)
+	}
+	return nil
+}
+
+// Check returns an error describing why body does not satisfy the assertion.
+func (a *ResponseAssertion) Check(body []byte) error {
+	switch a.Type {
+	case AssertionSubstring:
+		if !bytes.Contains(body, []byte(a.Expression)) {
+			return fmt.Errorf("response body does not contain %q", a.Expression)
+		}
+	case AssertionRegex:
+		re, err := regexp.Compile(a.Expression)
+		if err != nil {
+			return fmt.Errorf("invalid assertion regex: %w", err)
+		}
+		if !re.Match(body) {
+			return fmt.Errorf("response body does not match %q", a.Expression)
+		}
+	case AssertionJSONPath:
+		return a.checkJSONPath(body)
+	default:
+		return fmt.Errorf("invalid assertion type: %s", a.Type)
+	}
+	return nil
+}
+
+func (a *ResponseAssertion) checkJSONPath(body []byte) error {
+	path, err := parseJSONPath(a.Expression)
+	if err != nil {
+		return fmt.Errorf("invalid assertion JSONPath: %w", err)
+	}
+	var doc any
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fmt.Errorf("response body is not valid JSON: %w", err)
+	}
+
+	node := doc
+	for _, step := range path {
+		var ok bool
+		if node, ok = step.apply(node); !ok {
+			return fmt.Errorf("JSONPath %s not found in response body", a.Expression)
+		}
+	}
+	if a.Value == "" {
+		return nil
+	}
+
+	actual, ok := node.(string)
+	if !ok {
+		raw, _ := json.Marshal(node)
+		actual = string(raw)
+	}
+	if actual != a.Value {
+		return fmt.Errorf("JSONPath %s is %s, expected %s", a.Expression, actual, a.Value)
+	}
+	return nil
+}
+
+// jsonPathStep selects an object member (key) or an array element (index).
+type jsonPathStep struct {
+	key   string
+	index int
+	isKey bool
+}
+
+func (s jsonPathStep) apply(node any) (any, bool) {
+	if s.isKey {
+		obj, ok := node.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		v, ok := obj[s.key]
+		return v, ok
+	}
+	arr, ok := node.([]any)
+	if !ok {
+		return nil, false
+	}
+	i := s.index
+	if i < 0 {
+		i += len(arr)
+	}
+	if i < 0 || i >= len(arr) {
+		return nil, false
+	}
+	return arr[i], true
+}
+
+// parseJSONPath parses the subset of JSONPath made of member and index selectors,
+// e.g. $.data.items[0].name or $['status'].
+func parseJSONPath(expr string) ([]jsonPathStep, error) {
+	if !strings.HasPrefix(expr, "$") {
+		return nil, fmt.Errorf("%q must start with $", expr)
+	}
+	var steps []jsonPathStep
+	rest := expr[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("%q has an empty member name", expr)
+			}
+			steps = append(steps, jsonPathStep{key: rest[:end], isKey: true})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%q has an unclosed [", expr)
+			}
+			selector := rest[1:end]
+			rest = rest[end+1:]
+			if len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0] {
+				steps = append(steps, jsonPathStep{key: selector[1 : len(selector)-1], isKey: true})
+				continue
+			}
+			index, err := strconv.Atoi(selector)
+			if err != nil {
+				return nil, fmt.Errorf("%q has an unsupported selector [%s]", expr, selector)
+			}
+			steps = append(steps, jsonPathStep{index: index})
+		default:
+			return nil, fmt.Errorf("%q has an unexpected character %q", expr, rest[0])
+		}
+	}
+	return steps, nil
+}
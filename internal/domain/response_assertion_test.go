@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseJSONPath(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    []jsonPathStep
+		wantErr bool
+	}{
+		{expr: "$", want: nil},
+		{expr: "$.status", want: []jsonPathStep{{key: "status", isKey: true}}},
+		{
+			expr: "$.data.items[0].name",
+			want: []jsonPathStep{{key: "data", isKey: true}, {key: "items", isKey: true}, {index: 0}, {key: "name", isKey: true}},
+		},
+		{expr: "$['status']", want: []jsonPathStep{{key: "status", isKey: true}}},
+		{expr: `$["a.b"]`, want: []jsonPathStep{{key: "a.b", isKey: true}}},
+		{expr: "$.items[-1]", want: []jsonPathStep{{key: "items", isKey: true}, {index: -1}}},
+		{expr: "$[0][1]", want: []jsonPathStep{{index: 0}, {index: 1}}},
+		{expr: "status", wantErr: true},
+		{expr: "$..status", wantErr: true},
+		{expr: "$.", wantErr: true},
+		{expr: "$.items[0", wantErr: true},
+		{expr: "$.items[*]", wantErr: true},
+		{expr: "$['status]", wantErr: true},
+		{expr: "$status", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := parseJSONPath(tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseJSONPath() = %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseJSONPath() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseJSONPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResponseAssertionCheck(t *testing.T) {
+	const body = `{"status":"ok","count":3,"ready":true,"data":{"items":[{"name":"a"},{"name":"b"}]},"none":null}`
+
+	tests := []struct {
+		name      string
+		assertion ResponseAssertion
+		body      string
+		wantErr   bool
+	}{
+		{name: "substring found", assertion: ResponseAssertion{Type: AssertionSubstring, Expression: `"ok"`}},
+		{name: "substring missing", assertion: ResponseAssertion{Type: AssertionSubstring, Expression: "fail"}, wantErr: true},
+		{name: "regex matches", assertion: ResponseAssertion{Type: AssertionRegex, Expression: `"count":\d+`}},
+		{name: "regex does not match", assertion: ResponseAssertion{Type: AssertionRegex, Expression: `"count":"\d+"`}, wantErr: true},
+		{name: "jsonpath exists", assertion: ResponseAssertion{Type: AssertionJSONPath, Expression: "$.data.items[1].name"}},
+		{name: "jsonpath to null exists", assertion: ResponseAssertion{Type: AssertionJSONPath, Expression: "$.none"}},
+		{name: "jsonpath missing member", assertion: ResponseAssertion{Type: AssertionJSONPath, Expression: "$.data.total"}, wantErr: true},
+		{name: "jsonpath index out of range", assertion: ResponseAssertion{Type: AssertionJSONPath, Expression: "$.data.items[2]"}, wantErr: true},
+		{name: "jsonpath negative index", assertion: ResponseAssertion{Type: AssertionJSONPath, Expression: "$.data.items[-1].name", Value: "b"}},
+		{name: "jsonpath index on an object", assertion: ResponseAssertion{Type: AssertionJSONPath, Expression: "$.data[0]"}, wantErr: true},
+		{name: "jsonpath member of an array", assertion: ResponseAssertion{Type: AssertionJSONPath, Expression: "$.data.items.name"}, wantErr: true},
+		{name: "jsonpath string value", assertion: ResponseAssertion{Type: AssertionJSONPath, Expression: "$['status']", Value: "ok"}},
+		{name: "jsonpath string value differs", assertion: ResponseAssertion{Type: AssertionJSONPath, Expression: "$.status", Value: "error"}, wantErr: true},
+		{name: "jsonpath number compares as JSON", assertion: ResponseAssertion{Type: AssertionJSONPath, Expression: "$.count", Value: "3"}},
+		{name: "jsonpath bool compares as JSON", assertion: ResponseAssertion{Type: AssertionJSONPath, Expression: "$.ready", Value: "true"}},
+		{name: "jsonpath object compares as JSON", assertion: ResponseAssertion{Type: AssertionJSONPath, Expression: "$.data.items[0]", Value: `{"name":"a"}`}},
+		{name: "jsonpath on invalid JSON", assertion: ResponseAssertion{Type: AssertionJSONPath, Expression: "$.status"}, body: "ok", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.body
+			if b == "" {
+				b = body
+			}
+			err := tt.assertion.Check([]byte(b))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestResponseAssertionValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		assertion ResponseAssertion
+		wantErr   bool
+	}{
+		{name: "substring", assertion: ResponseAssertion{Type: AssertionSubstring, Expression: "ok"}},
+		{name: "empty expression", assertion: ResponseAssertion{Type: AssertionSubstring}, wantErr: true},
+		{name: "invalid regex", assertion: ResponseAssertion{Type: AssertionRegex, Expression: "("}, wantErr: true},
+		{name: "unsupported JSONPath", assertion: ResponseAssertion{Type: AssertionJSONPath, Expression: "$.items[*]"}, wantErr: true},
+		{name: "unknown type", assertion: ResponseAssertion{Type: "xpath", Expression: "/a"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.assertion.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
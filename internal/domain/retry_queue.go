@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// RetryRequest asks the leader to dispatch the next attempt of a failed execution
+// to a worker that has not attempted it yet.
+type RetryRequest struct {
+	JobName          string    `json:"job_name"`
+	ExecutionID      string    `json:"execution_id"`
+	Attempt          int       `json:"attempt"`            // Number of the attempt to dispatch
+	ExcludeWorkerIDs []string  `json:"exclude_worker_ids"` // Workers that already failed this execution
+	NotBefore        time.Time `json:"not_before"`         // Earliest dispatch time, honouring the retry backoff
+}
+
+// RetryQueue hands failed attempts from workers to the leader for re-dispatch.
+type RetryQueue interface {
+	// Enqueue stores a retry request until the leader handles it.
+	Enqueue(ctx context.Context, req *RetryRequest) error
+	// Watch emits every pending request, including those enqueued before the call.
+	// The channel is closed once ctx is cancelled.
+	Watch(ctx context.Context) <-chan *RetryRequest
+	// Remove deletes a request. It returns false if the request was already removed,
+	// so that only one node handles it.
+	Remove(ctx context.Context, req *RetryRequest) (bool, error)
+}
@@ -1,6 +1,9 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type Schedular interface {
 	Start(ctx context.Context) error
@@ -8,4 +11,18 @@ type Schedular interface {
 
 	AddJob(job *Job) error
 	RemoveJob(name string) error
+	// Clear unschedules every job, keeping their persisted fire times.
+	Clear()
+}
+
+// FireTimeRepository persists the last time the scheduler fired each job, so that
+// a newly elected leader can work out which runs were missed during failover.
+type FireTimeRepository interface {
+	// SaveLastFireTime records the most recent fire time of a job.
+	SaveLastFireTime(ctx context.Context, jobName string, firedAt time.Time) error
+	// GetLastFireTime returns the most recent fire time of a job,
+	// or the zero time if the job has never been fired.
+	GetLastFireTime(ctx context.Context, jobName string) (time.Time, error)
+	// DeleteLastFireTime forgets the fire time of a removed job.
+	DeleteLastFireTime(ctx context.Context, jobName string) error
 }
@@ -1,8 +1,32 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// ExecutionResult describes how the work done by an executor ended and what it used.
+type ExecutionResult struct {
+	ExitCode    *int          // Exit code of the process, -1 if it was ended by a signal; nil if no process was run
+	Signal      string        // Signal that ended the process, e.g. "SIGKILL"; empty if it exited on its own
+	UserCPU     time.Duration // CPU time spent in user mode by the process and its waited-for children
+	SystemCPU   time.Duration // CPU time spent in kernel mode by the process and its waited-for children
+	MaxRSS      int64         // Peak resident set size in bytes
+	OutputBytes int64         // Bytes of output produced, e.g. stdout and stderr or the HTTP response body
+	HTTPStatus  int           // Status code of the HTTP response
+	OOMKilled   bool          // A process was killed for exceeding the job's memory limit
+}
 
 // TaskExecutor defines the interface for executing a job's action.
+// Output is written to output as it is produced, so it can be streamed while the job runs.
+// The result is returned even when the execution failed, as far as it is known.
 type TaskExecutor interface {
-	Execute(ctx context.Context, job *Job) (output string, err error)
+	Execute(ctx context.Context, job *Job, output io.Writer) (*ExecutionResult, error)
+}
+
+// PreflightChecker is implemented by executors that can tell, before accepting a job,
+// whether this worker is able to run it.
+type PreflightChecker interface {
+	Preflight(job *Job) error
 }
@@ -0,0 +1,59 @@
+// internal/domain/worker.go
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// WorkerRegistration is what a worker publishes about itself in the worker registry.
+// The master uses it to find workers and to spread executions across them.
+type WorkerRegistration struct {
+	Addr     string `json:"addr"`
+	Running  int    `json:"running"`  // Executions in progress on the worker
+	Capacity int    `json:"capacity"` // Executions the worker is sized to run at once
+	// Labels describe the worker, e.g. zone=dmz or has=psql, for jobs to select it by.
+	Labels       map[string]string `json:"labels,omitempty"`
+	RegisteredAt time.Time         `json:"registered_at,omitzero"`
+}
+
+// CircuitState is the state of the circuit breaker the master keeps for a worker.
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // The worker is healthy and may be selected
+	CircuitOpen     CircuitState = "open"      // The worker failed repeatedly and is not selected until its cool-down ends
+	CircuitHalfOpen CircuitState = "half_open" // The cool-down ended; the next probe or dispatch decides whether the circuit closes
+)
+
+// WorkerHealth is the health of a worker as tracked by the master.
+type WorkerHealth struct {
+	Circuit             CircuitState `json:"circuit"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	LastError           string       `json:"last_error,omitempty"`
+	LastProbeAt         time.Time    `json:"last_probe_at,omitzero"`
+	OpenUntil           time.Time    `json:"open_until,omitzero"` // End of the cool-down while the circuit is open
+}
+
+// WorkerStatus describes a registered worker: what it published about itself and how
+// healthy the master considers it.
+type WorkerStatus struct {
+	ID       string            `json:"id"`
+	Addr     string            `json:"addr"`
+	Running  int               `json:"running"`
+	Capacity int               `json:"capacity"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Health   WorkerHealth      `json:"health"`
+
+	RegisteredAt    time.Time `json:"registered_at,omitzero"`  // Zero for workers that do not publish it
+	LeaseTTLSeconds int64     `json:"lease_ttl_seconds"`       // Time left before the registration expires unless renewed; zero if unknown
+	LastHeartbeat   time.Time `json:"last_heartbeat,omitzero"` // When the worker last renewed its registration lease
+}
+
+// WorkerDirectory lists the workers known to the master.
+type WorkerDirectory interface {
+	// ListWorkers returns the registered workers, ordered by ID.
+	ListWorkers(ctx context.Context) ([]WorkerStatus, error)
+	// GetWorker returns a registered worker, or ErrWorkerNotFound.
+	GetWorker(ctx context.Context, workerID string) (*WorkerStatus, error)
+}
@@ -0,0 +1,60 @@
+// internal/infra/archive/fs_execution_archive.go
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"distributed-cron/internal/domain"
+)
+
+// fsExecutionArchive appends pruned execution records, one JSON object per line, to
+// {dir}/{jobName}.jsonl on the local file system.
+type fsExecutionArchive struct {
+	dir string
+}
+
+// NewFSExecutionArchive creates an archive under dir, creating the directory if needed.
+func NewFSExecutionArchive(dir string) (domain.ExecutionArchive, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create archive directory %s: %w", dir, err)
+	}
+	return &fsExecutionArchive{dir: dir}, nil
+}
+
+// Archive writes the records of each job with a single append and syncs the file, so
+// that the records are on disk before they are deleted from the history.
+func (a *fsExecutionArchive) Archive(ctx context.Context, records []*domain.ExecutionRecord) error {
+	byJob := make(map[string][]byte)
+	for _, record := range records {
+		line, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal execution record %s to JSON: %w", record.ID, err)
+		}
+		byJob[record.JobName] = append(append(byJob[record.JobName], line...), '\n')
+	}
+
+	for jobName, lines := range byJob {
+		// The job name is escaped, so that it cannot point outside of the archive.
+		path := filepath.Join(a.dir, url.PathEscape(jobName)+".jsonl")
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return fmt.Errorf("failed to open archive of job %s: %w", jobName, err)
+		}
+		_, err = f.Write(lines)
+		if err == nil {
+			err = f.Sync()
+		}
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write archive of job %s: %w", jobName, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,243 @@
+// internal/infra/etcd/etcd_execution_query.go
+package etcd
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"distributed-cron/internal/domain"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+const (
+	// ExecutionQueryDir holds the secondary indexes used to query execution records across jobs:
+	//   /cron/history-query/time/{seq}
+	//   /cron/history-query/status/{status}/{seq}
+	//   /cron/history-query/worker/{workerID}/{seq}
+	// Each key holds a queryRef, and seq is the key suffix of the record under ExecutionHistoryDir.
+	ExecutionQueryDir = "/cron/history-query/"
+)
+
+// maxQueryScanPages bounds how many keys one query reads, as a multiple of its page size,
+// so that a filter that matches little cannot turn a request into a scan of the whole
+// history. The client continues with the returned cursor instead.
+const maxQueryScanPages = 10
+
+// queryRef is the value of a query index key. It carries what is needed to filter and
+// to find the record without reading it.
+type queryRef struct {
+	JobName  string                 `json:"job_name"`
+	Status   domain.ExecutionStatus `json:"status"`
+	WorkerID string                 `json:"worker_id,omitempty"`
+}
+
+func queryTimePrefix() string {
+	return ExecutionQueryDir + "time/"
+}
+
+func queryStatusPrefix(status domain.ExecutionStatus) string {
+	return path.Join(ExecutionQueryDir, "status", string(status)) + "/"
+}
+
+func queryWorkerPrefix(workerID string) string {
+	return path.Join(ExecutionQueryDir, "worker", workerID) + "/"
+}
+
+// queryIndexOps returns the operations that point the query index keys of a record at
+// entry, and remove the keys that previous was indexed under but entry is not.
+func queryIndexOps(jobName string, previous, entry *historyIndexEntry) ([]clientv3.Op, error) {
+	ref, err := json.Marshal(queryRef{JobName: jobName, Status: entry.Status, WorkerID: entry.WorkerID})
+	if err != nil {
+		return nil, err
+	}
+
+	ops := []clientv3.Op{clientv3.OpPut(queryTimePrefix()+entry.Seq, string(ref))}
+	if entry.Status != "" {
+		ops = append(ops, clientv3.OpPut(queryStatusPrefix(entry.Status)+entry.Seq, string(ref)))
+	}
+	if entry.WorkerID != "" {
+		ops = append(ops, clientv3.OpPut(queryWorkerPrefix(entry.WorkerID)+entry.Seq, string(ref)))
+	}
+	if previous != nil {
+		if previous.Status != "" && previous.Status != entry.Status {
+			ops = append(ops, clientv3.OpDelete(queryStatusPrefix(previous.Status)+previous.Seq))
+		}
+		if previous.WorkerID != "" && previous.WorkerID != entry.WorkerID {
+			ops = append(ops, clientv3.OpDelete(queryWorkerPrefix(previous.WorkerID)+previous.Seq))
+		}
+	}
+	return ops, nil
+}
+
+// queryIndexDeletes returns the operations that remove the query index keys of a record.
+func queryIndexDeletes(entry *historyIndexEntry) []clientv3.Op {
+	ops := []clientv3.Op{clientv3.OpDelete(queryTimePrefix() + entry.Seq)}
+	if entry.Status != "" {
+		ops = append(ops, clientv3.OpDelete(queryStatusPrefix(entry.Status)+entry.Seq))
+	}
+	if entry.WorkerID != "" {
+		ops = append(ops, clientv3.OpDelete(queryWorkerPrefix(entry.WorkerID)+entry.Seq))
+	}
+	return ops
+}
+
+// Query scans the most selective key range the query allows: the job's own history, or
+// else the worker, status or time index. The time bounds narrow the range, since all of
+// them are ordered newest first, and the remaining filters are applied to what is read.
+func (r *etcdExecutionRepository) Query(ctx context.Context, q domain.ExecutionQuery) ([]*domain.ExecutionRecord, string, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.etcd.QueryExecutions")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("job.name", q.JobName),
+		attribute.String("status", string(q.Status)),
+		attribute.String("worker.id", q.WorkerID),
+		attribute.String("cursor", q.Cursor),
+		attribute.Int("limit", q.Limit),
+	)
+
+	var prefix string
+	switch {
+	case q.JobName != "":
+		prefix = historyPrefix(q.JobName)
+	case q.WorkerID != "":
+		prefix = queryWorkerPrefix(q.WorkerID)
+	case q.Status != "":
+		prefix = queryStatusPrefix(q.Status)
+	default:
+		prefix = queryTimePrefix()
+	}
+
+	start, end := prefix, clientv3.GetPrefixRangeEnd(prefix)
+	if !q.Until.IsZero() {
+		start = prefix + seqTime(q.Until)
+	}
+	if !q.Since.IsZero() {
+		// Up to and including the records that started at Since.
+		end = prefix + seqTime(q.Since.Add(-time.Nanosecond))
+	}
+	if q.Cursor != "" {
+		after, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+		if err != nil || len(after) == 0 {
+			return nil, "", fmt.Errorf("%w: %q", domain.ErrInvalidCursor, q.Cursor)
+		}
+		if cursorStart := prefix + string(after) + "\x00"; cursorStart > start {
+			start = cursorStart
+		}
+	}
+
+	var (
+		records []*domain.ExecutionRecord
+		refs    []string // Record keys found through an index, read once the scan is done
+		lastKey string
+		more    bool
+	)
+	matched := func() int { return len(records) + len(refs) }
+	for scanned := 0; start < end && matched() < q.Limit && scanned < maxQueryScanPages*q.Limit; {
+		resp, err := r.client.Get(ctx, start, clientv3.WithRange(end), clientv3.WithLimit(int64(q.Limit)))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to query execution records from etcd")
+			return nil, "", fmt.Errorf("failed to query execution records from etcd: %w", err)
+		}
+
+		consumed := 0
+		for _, kv := range resp.Kvs {
+			if matched() == q.Limit {
+				break
+			}
+			consumed++
+			lastKey = string(kv.Key)
+
+			if q.JobName != "" {
+				var record domain.ExecutionRecord
+				if err := json.Unmarshal(kv.Value, &record); err != nil {
+					r.logger.Warn("failed to unmarshal execution record from etcd", "key", lastKey, "error", err)
+					continue
+				}
+				record.Revision = kv.ModRevision
+				if queryMatches(q, record.JobName, record.Status, record.WorkerID) {
+					records = append(records, &record)
+				}
+				continue
+			}
+
+			var ref queryRef
+			if err := json.Unmarshal(kv.Value, &ref); err != nil {
+				r.logger.Warn("failed to unmarshal execution query index entry from etcd", "key", lastKey, "error", err)
+				continue
+			}
+			if queryMatches(q, ref.JobName, ref.Status, ref.WorkerID) {
+				refs = append(refs, historyPrefix(ref.JobName)+strings.TrimPrefix(lastKey, prefix))
+			}
+		}
+		scanned += consumed
+		more = resp.More || consumed < len(resp.Kvs)
+		if !more {
+			break
+		}
+		start = lastKey + "\x00"
+	}
+
+	if len(refs) > 0 {
+		indexed, err := r.getRecords(ctx, refs)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to get execution records from etcd")
+			return nil, "", err
+		}
+		records = indexed
+	}
+
+	var next string
+	if more && lastKey != "" {
+		next = base64.RawURLEncoding.EncodeToString([]byte(strings.TrimPrefix(lastKey, prefix)))
+	}
+	span.SetAttributes(attribute.Int("records_returned", len(records)), attribute.Bool("has_more", next != ""))
+	return records, next, nil
+}
+
+// queryMatches applies the filters of q that the scanned key range does not already
+// apply. Since the range is chosen from the same fields, checking all of them is harmless.
+func queryMatches(q domain.ExecutionQuery, jobName string, status domain.ExecutionStatus, workerID string) bool {
+	return (q.JobName == "" || q.JobName == jobName) &&
+		(q.Status == "" || q.Status == status) &&
+		(q.WorkerID == "" || q.WorkerID == workerID)
+}
+
+// getRecords reads the records at keys, in order. Records deleted in the meantime are left out.
+func (r *etcdExecutionRepository) getRecords(ctx context.Context, keys []string) ([]*domain.ExecutionRecord, error) {
+	records := make([]*domain.ExecutionRecord, 0, len(keys))
+	for len(keys) > 0 {
+		chunk := keys[:min(maxTxnOps, len(keys))]
+		keys = keys[len(chunk):]
+
+		gets := make([]clientv3.Op, len(chunk))
+		for i, key := range chunk {
+			gets[i] = clientv3.OpGet(key)
+		}
+		resp, err := r.client.Txn(ctx).Then(gets...).Commit()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get execution records from etcd: %w", err)
+		}
+		for _, op := range resp.Responses {
+			for _, kv := range op.GetResponseRange().GetKvs() {
+				var record domain.ExecutionRecord
+				if err := json.Unmarshal(kv.Value, &record); err != nil {
+					r.logger.Warn("failed to unmarshal execution record from etcd", "key", string(kv.Key), "error", err)
+					continue
+				}
+				record.Revision = kv.ModRevision
+				records = append(records, &record)
+			}
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,285 @@
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"distributed-cron/internal/domain"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// describeOps renders ops as "put key" and "delete key", sorted.
+func describeOps(ops []clientv3.Op) []string {
+	described := make([]string, len(ops))
+	for i, op := range ops {
+		kind := "delete"
+		if op.IsPut() {
+			kind = "put"
+		}
+		described[i] = kind + " " + string(op.KeyBytes())
+	}
+	slices.Sort(described)
+	return described
+}
+
+func TestQueryIndexOps(t *testing.T) {
+	const seq = "7431264897102417089-e1"
+	tests := []struct {
+		name     string
+		previous *historyIndexEntry
+		entry    historyIndexEntry
+		want     []string
+	}{
+		{
+			name:  "new record",
+			entry: historyIndexEntry{Seq: seq, Status: domain.ExecutionStatusRunning, WorkerID: "w1"},
+			want: []string{
+				"put /cron/history-query/status/running/" + seq,
+				"put /cron/history-query/time/" + seq,
+				"put /cron/history-query/worker/w1/" + seq,
+			},
+		},
+		{
+			name:  "record without a worker",
+			entry: historyIndexEntry{Seq: seq, Status: domain.ExecutionStatusFailed},
+			want: []string{
+				"put /cron/history-query/status/failed/" + seq,
+				"put /cron/history-query/time/" + seq,
+			},
+		},
+		{
+			name:     "status and worker changed",
+			previous: &historyIndexEntry{Seq: seq, Status: domain.ExecutionStatusRunning, WorkerID: "w1"},
+			entry:    historyIndexEntry{Seq: seq, Status: domain.ExecutionStatusSuccess, WorkerID: "w2"},
+			want: []string{
+				"delete /cron/history-query/status/running/" + seq,
+				"delete /cron/history-query/worker/w1/" + seq,
+				"put /cron/history-query/status/success/" + seq,
+				"put /cron/history-query/time/" + seq,
+				"put /cron/history-query/worker/w2/" + seq,
+			},
+		},
+		{
+			name:     "unchanged keys are not deleted",
+			previous: &historyIndexEntry{Seq: seq, Status: domain.ExecutionStatusRunning, WorkerID: "w1"},
+			entry:    historyIndexEntry{Seq: seq, Status: domain.ExecutionStatusRunning, WorkerID: "w1"},
+			want: []string{
+				"put /cron/history-query/status/running/" + seq,
+				"put /cron/history-query/time/" + seq,
+				"put /cron/history-query/worker/w1/" + seq,
+			},
+		},
+		{
+			name:     "entry written before the query index",
+			previous: &historyIndexEntry{Seq: seq},
+			entry:    historyIndexEntry{Seq: seq, Status: domain.ExecutionStatusSuccess},
+			want: []string{
+				"put /cron/history-query/status/success/" + seq,
+				"put /cron/history-query/time/" + seq,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ops, err := queryIndexOps("report", tt.previous, &tt.entry)
+			if err != nil {
+				t.Fatalf("queryIndexOps() error = %v", err)
+			}
+			if got := describeOps(ops); !slices.Equal(got, tt.want) {
+				t.Errorf("queryIndexOps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryIndexDeletes(t *testing.T) {
+	const seq = "7431264897102417089-e1"
+	got := describeOps(queryIndexDeletes(&historyIndexEntry{Seq: seq, Status: domain.ExecutionStatusSuccess, WorkerID: "w1"}))
+	want := []string{
+		"delete /cron/history-query/status/success/" + seq,
+		"delete /cron/history-query/time/" + seq,
+		"delete /cron/history-query/worker/w1/" + seq,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("queryIndexDeletes() = %v, want %v", got, want)
+	}
+}
+
+// saveRecords saves one record per status in statuses, the first one newest, a minute apart.
+func saveRecords(t *testing.T, repo *etcdExecutionRepository, jobName, workerID string, statuses ...domain.ExecutionStatus) {
+	t.Helper()
+	newest := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	for i, status := range statuses {
+		record := &domain.ExecutionRecord{
+			ID:        fmt.Sprintf("%s-%02d", jobName, i),
+			JobName:   jobName,
+			Status:    status,
+			WorkerID:  workerID,
+			StartTime: newest.Add(-time.Duration(i) * time.Minute),
+		}
+		if err := repo.Save(context.Background(), record); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+}
+
+func recordIDs(records []*domain.ExecutionRecord) []string {
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+	return ids
+}
+
+func TestQueryAcrossJobs(t *testing.T) {
+	repo, _ := newTestExecutionRepository(t)
+	saveRecords(t, repo, "a", "w1", domain.ExecutionStatusFailed, domain.ExecutionStatusSuccess)
+	saveRecords(t, repo, "b", "w2", domain.ExecutionStatusSuccess, domain.ExecutionStatusFailed)
+
+	tests := []struct {
+		name  string
+		query domain.ExecutionQuery
+		want  []string
+	}{
+		{name: "all, newest first", query: domain.ExecutionQuery{Limit: 10}, want: []string{"a-00", "b-00", "a-01", "b-01"}},
+		{name: "by status", query: domain.ExecutionQuery{Status: domain.ExecutionStatusFailed, Limit: 10}, want: []string{"a-00", "b-01"}},
+		{name: "by worker", query: domain.ExecutionQuery{WorkerID: "w2", Limit: 10}, want: []string{"b-00", "b-01"}},
+		{name: "by worker and status", query: domain.ExecutionQuery{WorkerID: "w2", Status: domain.ExecutionStatusFailed, Limit: 10}, want: []string{"b-01"}},
+		{name: "by job and status", query: domain.ExecutionQuery{JobName: "a", Status: domain.ExecutionStatusSuccess, Limit: 10}, want: []string{"a-01"}},
+		{
+			name:  "by time",
+			query: domain.ExecutionQuery{Since: time.Date(2024, 3, 10, 8, 59, 0, 0, time.UTC), Until: time.Date(2024, 3, 10, 8, 59, 0, 0, time.UTC), Limit: 10},
+			want:  []string{"a-01", "b-01"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, next, err := repo.Query(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if got := recordIDs(records); !slices.Equal(got, tt.want) || next != "" {
+				t.Errorf("Query() = %v, %q, want %v, \"\"", got, next, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryStopsAfterMaxScanPages(t *testing.T) {
+	repo, _ := newTestExecutionRepository(t)
+	const limit = 2
+	statuses := slices.Repeat([]domain.ExecutionStatus{domain.ExecutionStatusSuccess}, maxQueryScanPages*limit+5)
+	statuses = append(statuses, domain.ExecutionStatusFailed)
+	saveRecords(t, repo, "report", "w1", statuses...)
+
+	// The status filter is applied while scanning the job's history, so only one page in
+	// maxQueryScanPages may be read before the query returns with a cursor.
+	q := domain.ExecutionQuery{JobName: "report", Status: domain.ExecutionStatusFailed, Limit: limit}
+	records, next, err := repo.Query(context.Background(), q)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(records) != 0 || next == "" {
+		t.Fatalf("Query() = %v, %q, want no records and a cursor", recordIDs(records), next)
+	}
+
+	q.Cursor = next
+	records, next, err = repo.Query(context.Background(), q)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	want := fmt.Sprintf("report-%02d", len(statuses)-1)
+	if got := recordIDs(records); !slices.Equal(got, []string{want}) || next != "" {
+		t.Errorf("Query() = %v, %q, want [%s], \"\"", got, next, want)
+	}
+}
+
+func TestBackfillQueryIndex(t *testing.T) {
+	ctx := context.Background()
+	client, kv := newFakeClient()
+	repo := NewEtcdExecutionRepository(client, slog.New(slog.DiscardHandler))
+
+	// A record saved in the time-ordered layout before the query index existed.
+	record := &domain.ExecutionRecord{ID: "e1", JobName: "report", Status: domain.ExecutionStatusFailed, WorkerID: "w1", StartTime: time.Now()}
+	seq := historySeq(record)
+	kv.Put(ctx, historyPrefix("report")+seq, `{"id":"e1","job_name":"report","status":"failed","worker_id":"w1"}`)
+	kv.Put(ctx, historyIndexKey("report", "e1"), `{"seq":"`+seq+`"}`)
+
+	NewEtcdHistoryMigrator(client, slog.New(slog.DiscardHandler)).Run(ctx)
+
+	var indexed []string
+	for key := range kv.entries {
+		if strings.HasPrefix(key, ExecutionQueryDir) {
+			indexed = append(indexed, key)
+		}
+	}
+	slices.Sort(indexed)
+	want := []string{
+		queryStatusPrefix(domain.ExecutionStatusFailed) + seq,
+		queryTimePrefix() + seq,
+		queryWorkerPrefix("w1") + seq,
+	}
+	if !slices.Equal(indexed, want) {
+		t.Fatalf("query index keys = %v, want %v", indexed, want)
+	}
+	if _, ok := kv.entries[HistoryMigrationDir+"query-index"]; !ok {
+		t.Errorf("query-index migration not recorded as done")
+	}
+
+	// The backfilled record keeps a revision Save accepts, and a later Save moves its
+	// status key instead of leaving the old one behind.
+	got, err := repo.Get(ctx, "report", "e1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	got.Status = domain.ExecutionStatusSuccess
+	if err := repo.Save(ctx, got); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if _, ok := kv.entries[queryStatusPrefix(domain.ExecutionStatusFailed)+seq]; ok {
+		t.Errorf("status key of the previous status left behind")
+	}
+	records, _, err := repo.Query(ctx, domain.ExecutionQuery{Status: domain.ExecutionStatusSuccess, Limit: 10})
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if ids := recordIDs(records); !slices.Equal(ids, []string{"e1"}) {
+		t.Errorf("Query() = %v, want [e1]", ids)
+	}
+}
+
+func TestDeleteRetriesWhenSavedConcurrently(t *testing.T) {
+	ctx := context.Background()
+	repo, kv := newTestExecutionRepository(t)
+	saveRecords(t, repo, "report", "w1", domain.ExecutionStatusRunning)
+
+	// Between Delete reading the index entry and deleting, the record is saved with a new
+	// status; the delete must remove the new status key, not the one it read.
+	kv.beforeCommit = func() {
+		kv.beforeCommit = func() {
+			record, err := repo.Get(ctx, "report", "report-00")
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			record.Status = domain.ExecutionStatusFailed
+			if err := repo.Save(ctx, record); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+		}
+	}
+	deleted, err := repo.Delete(ctx, "report", []string{"report-00"})
+	if err != nil || deleted != 1 {
+		t.Fatalf("Delete() = %d, %v, want 1, nil", deleted, err)
+	}
+	for key := range kv.entries {
+		t.Errorf("key %s left behind", key)
+	}
+}
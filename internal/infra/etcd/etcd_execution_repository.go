@@ -3,10 +3,14 @@ package etcd
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math"
 	"path"
+	"strings"
+	"time"
 
 	"distributed-cron/internal/domain"
 
@@ -18,9 +22,21 @@ import (
 )
 
 const (
+	// ExecutionHistoryDir holds execution records, keyed by /cron/history/{jobName}/{seq}.
+	// seq sorts newest first, so a page of history is a single limited range read.
 	ExecutionHistoryDir = "/cron/history/"
+	// ExecutionIndexDir maps /cron/history-index/{jobName}/{executionID} to the seq of the record.
+	ExecutionIndexDir = "/cron/history-index/"
 )
 
+// historyIndexEntry is the value of an index key. Status and WorkerID are the ones the
+// query index keys of the record were last written for, so that Save and Delete can find them.
+type historyIndexEntry struct {
+	Seq      string                 `json:"seq"`
+	Status   domain.ExecutionStatus `json:"status"`
+	WorkerID string                 `json:"worker_id,omitempty"`
+}
+
 type etcdExecutionRepository struct {
 	client *clientv3.Client
 	logger *slog.Logger
@@ -36,11 +52,58 @@ func NewEtcdExecutionRepository(client *clientv3.Client, logger *slog.Logger) do
 	}
 }
 
-// Save persists a single execution record to etcd.
-// The key is structured as /cron/history/{jobName}/{executionID}.
+// historySeq returns the key suffix of a record: its start time subtracted from the
+// largest int64, zero padded, so that lexical key order is newest first. The execution
+// ID keeps keys of executions started in the same nanosecond apart.
+func historySeq(record *domain.ExecutionRecord) string {
+	return seqTime(record.StartTime) + "-" + record.ID
+}
+
+// seqTime returns the time part of a seq; keys of records that started at or before t
+// sort at or after it.
+func seqTime(t time.Time) string {
+	return fmt.Sprintf("%019d", math.MaxInt64-t.UnixNano())
+}
+
+func historyPrefix(jobName string) string {
+	return path.Join(ExecutionHistoryDir, jobName) + "/"
+}
+
+func historyIndexKey(jobName, executionID string) string {
+	return path.Join(ExecutionIndexDir, jobName, executionID)
+}
+
+// lookupIndex returns the index entry of an execution and its mod revision, or nil and 0
+// if it has none.
+func (r *etcdExecutionRepository) lookupIndex(ctx context.Context, jobName, executionID string) (*historyIndexEntry, int64, error) {
+	resp, err := r.client.Get(ctx, historyIndexKey(jobName, executionID))
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(resp.Kvs) == 0 {
+		return nil, 0, nil
+	}
+	entry, err := decodeIndexEntry(resp.Kvs[0].Value)
+	return entry, resp.Kvs[0].ModRevision, err
+}
+
+func decodeIndexEntry(value []byte) (*historyIndexEntry, error) {
+	var entry historyIndexEntry
+	if err := json.Unmarshal(value, &entry); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal execution index entry: %w", err)
+	}
+	return &entry, nil
+}
+
+// Save persists a single execution record to etcd together with its index entry and
+// query index keys. A record saved again keeps the key it was first saved under.
 func (r *etcdExecutionRepository) Save(ctx context.Context, record *domain.ExecutionRecord) error {
 	ctx, span := r.tracer.Start(ctx, "repo.etcd.SaveExecution")
 	defer span.End()
+	span.SetAttributes(
+		attribute.String("execution.id", record.ID),
+		attribute.String("job.name", record.JobName),
+	)
 
 	recordJSON, err := json.Marshal(record)
 	if err != nil {
@@ -49,19 +112,55 @@ func (r *etcdExecutionRepository) Save(ctx context.Context, record *domain.Execu
 		return fmt.Errorf("failed to marshal execution record %s to JSON: %w", record.ID, err)
 	}
 
-	key := path.Join(ExecutionHistoryDir, record.JobName, record.ID)
-	span.SetAttributes(
-		attribute.String("execution.id", record.ID),
-		attribute.String("job.name", record.JobName),
-		attribute.String("etcd.key", key),
-	)
+	indexKey := historyIndexKey(record.JobName, record.ID)
+	previous, indexRev, err := r.lookupIndex(ctx, record.JobName, record.ID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to get execution index from etcd")
+		return fmt.Errorf("failed to look up execution record %s in etcd: %w", record.ID, err)
+	}
+	// The index entry is written together with the record, so its mod revision is the
+	// revision the record was last saved with.
+	if indexRev != record.Revision {
+		span.SetStatus(codes.Error, "execution record changed concurrently")
+		return fmt.Errorf("%w: %s/%s", domain.ErrExecutionConflict, record.JobName, record.ID)
+	}
+	entry := historyIndexEntry{Seq: historySeq(record), Status: record.Status, WorkerID: record.WorkerID}
+	if previous != nil {
+		entry.Seq = previous.Seq
+	}
+	entryJSON, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal execution index entry %s to JSON: %w", record.ID, err)
+	}
+	key := historyPrefix(record.JobName) + entry.Seq
+	span.SetAttributes(attribute.String("etcd.key", key))
 
-	_, err = r.client.Put(ctx, key, string(recordJSON))
+	ops := []clientv3.Op{
+		clientv3.OpPut(key, string(recordJSON)),
+		clientv3.OpPut(indexKey, string(entryJSON)),
+	}
+	queryOps, err := queryIndexOps(record.JobName, previous, &entry)
+	if err != nil {
+		return fmt.Errorf("failed to build query index of execution record %s: %w", record.ID, err)
+	}
+	// Guarding on the index entry makes the lookup above and the writes atomic: the key,
+	// the query index keys removed and the stored revision all belong to the entry read.
+	// A record never saved must not exist yet.
+	resp, err := r.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(indexKey), "=", record.Revision)).
+		Then(append(ops, queryOps...)...).
+		Commit()
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "failed to put execution record to etcd")
 		return fmt.Errorf("failed to save execution record %s to etcd: %w", record.ID, err)
 	}
+	if !resp.Succeeded {
+		span.SetStatus(codes.Error, "execution record changed concurrently")
+		return fmt.Errorf("%w: %s/%s", domain.ErrExecutionConflict, record.JobName, record.ID)
+	}
+	record.Revision = resp.Header.Revision
 	return nil
 }
 
@@ -74,8 +173,17 @@ func (r *etcdExecutionRepository) Get(ctx context.Context, jobName, executionID
 		attribute.String("execution.id", executionID),
 	)
 
-	key := path.Join(ExecutionHistoryDir, jobName, executionID)
-	resp, err := r.client.Get(ctx, key)
+	entry, _, err := r.lookupIndex(ctx, jobName, executionID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to get execution index from etcd")
+		return nil, fmt.Errorf("failed to look up execution record %s/%s in etcd: %w", jobName, executionID, err)
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("%w: %s/%s", domain.ErrExecutionNotFound, jobName, executionID)
+	}
+
+	resp, err := r.client.Get(ctx, historyPrefix(jobName)+entry.Seq)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "failed to get execution record from etcd")
@@ -83,7 +191,7 @@ func (r *etcdExecutionRepository) Get(ctx context.Context, jobName, executionID
 	}
 
 	if len(resp.Kvs) == 0 {
-		return nil, fmt.Errorf("execution record %s/%s not found", jobName, executionID)
+		return nil, fmt.Errorf("%w: %s/%s", domain.ErrExecutionNotFound, jobName, executionID)
 	}
 
 	var record domain.ExecutionRecord
@@ -92,52 +200,248 @@ func (r *etcdExecutionRepository) Get(ctx context.Context, jobName, executionID
 		span.SetStatus(codes.Error, "failed to unmarshal execution record")
 		return nil, fmt.Errorf("failed to unmarshal execution record %s/%s from JSON: %w", jobName, executionID, err)
 	}
+	record.Revision = resp.Kvs[0].ModRevision
 	return &record, nil
 }
 
-// ListByJobName retrieves historical execution records for a specific job, with pagination.
-// Records are returned in reverse chronological order (newest first).
-func (r *etcdExecutionRepository) ListByJobName(ctx context.Context, jobName string, page, pageSize int) ([]*domain.ExecutionRecord, error) {
+// ListByJobName retrieves a page of execution records for a specific job, newest first.
+// The cursor is the seq of the last record of the previous page, base64 encoded; the
+// page is read with a single range request limited to pageSize keys.
+func (r *etcdExecutionRepository) ListByJobName(ctx context.Context, jobName, cursor string, pageSize int) ([]*domain.ExecutionRecord, string, error) {
 	ctx, span := r.tracer.Start(ctx, "repo.etcd.ListExecutions")
 	defer span.End()
 	span.SetAttributes(
 		attribute.String("job.name", jobName),
-		attribute.Int("page", page),
+		attribute.String("cursor", cursor),
 		attribute.Int("page_size", pageSize),
 	)
 
-	prefix := path.Join(ExecutionHistoryDir, jobName) + "/"
-	resp, err := r.client.Get(ctx, prefix,
-		clientv3.WithPrefix(),
-		clientv3.WithSort(clientv3.SortByCreateRevision, clientv3.SortDescend), // Newest first
+	prefix := historyPrefix(jobName)
+	start := prefix
+	if cursor != "" {
+		after, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || len(after) == 0 {
+			return nil, "", fmt.Errorf("%w: %q", domain.ErrInvalidCursor, cursor)
+		}
+		// The first key after the last one of the previous page.
+		start = prefix + string(after) + "\x00"
+	}
+
+	resp, err := r.client.Get(ctx, start,
+		clientv3.WithRange(clientv3.GetPrefixRangeEnd(prefix)),
+		clientv3.WithLimit(int64(pageSize)),
 	)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "failed to list execution records from etcd")
-		return nil, fmt.Errorf("failed to list execution records for job %s from etcd: %w", jobName, err)
+		return nil, "", fmt.Errorf("failed to list execution records for job %s from etcd: %w", jobName, err)
 	}
 
 	records := make([]*domain.ExecutionRecord, 0, len(resp.Kvs))
-	// Manual pagination for now. Etcd Get with Limit/Offset is for key-count, not index-based.
-	// For large number of records, this needs more robust pagination (e.g., cursor-based).
-	startIdx := (page - 1) * pageSize
-	endIdx := startIdx + pageSize
-
-	for i, kv := range resp.Kvs {
-		if i < startIdx {
-			continue // Skip records before the start of the current page
+	for _, kv := range resp.Kvs {
+		var record domain.ExecutionRecord
+		if err := json.Unmarshal(kv.Value, &record); err != nil {
+			r.logger.Warn("failed to unmarshal execution record from etcd", "key", string(kv.Key), "error", err)
+			continue
 		}
-		if i >= endIdx {
-			break // Stop once we have enough records for the current page
+		record.Revision = kv.ModRevision
+		records = append(records, &record)
+	}
+
+	var next string
+	if resp.More && len(resp.Kvs) > 0 {
+		last := strings.TrimPrefix(string(resp.Kvs[len(resp.Kvs)-1].Key), prefix)
+		next = base64.RawURLEncoding.EncodeToString([]byte(last))
+	}
+	span.SetAttributes(attribute.Int("records_returned", len(records)), attribute.Bool("has_more", next != ""))
+	return records, next, nil
+}
+
+// ListExpired finds where the expired records start, which is the earlier of the record
+// after the newest keep ones and the first record that started at or before before, and
+// reads up to limit records from there, or from after cursor if that is later.
+func (r *etcdExecutionRepository) ListExpired(ctx context.Context, jobName string, keep int, before time.Time, cursor string, limit int) ([]*domain.ExecutionRecord, string, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.etcd.ListExpiredExecutions")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("job.name", jobName),
+		attribute.Int("keep", keep),
+		attribute.String("before", before.Format(time.RFC3339)),
+		attribute.String("cursor", cursor),
+	)
+
+	prefix := historyPrefix(jobName)
+	end := clientv3.GetPrefixRangeEnd(prefix)
+
+	var start string
+	if !before.IsZero() {
+		start = prefix + seqTime(before)
+	}
+	if keep > 0 {
+		resp, err := r.client.Get(ctx, prefix, clientv3.WithRange(end), clientv3.WithLimit(int64(keep)), clientv3.WithKeysOnly())
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to list execution keys from etcd")
+			return nil, "", fmt.Errorf("failed to list execution records for job %s from etcd: %w", jobName, err)
 		}
+		if resp.More {
+			countStart := string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+			if start == "" || countStart < start {
+				start = countStart
+			}
+		}
+	}
+	if start == "" {
+		return nil, "", nil
+	}
+	if cursor != "" {
+		after, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || len(after) == 0 {
+			return nil, "", fmt.Errorf("%w: %q", domain.ErrInvalidCursor, cursor)
+		}
+		// The first key after the last one of the previous batch.
+		start = max(start, prefix+string(after)+"\x00")
+	}
 
+	resp, err := r.client.Get(ctx, start, clientv3.WithRange(end), clientv3.WithLimit(int64(limit)))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to list expired execution records from etcd")
+		return nil, "", fmt.Errorf("failed to list expired execution records for job %s from etcd: %w", jobName, err)
+	}
+
+	records := make([]*domain.ExecutionRecord, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
 		var record domain.ExecutionRecord
 		if err := json.Unmarshal(kv.Value, &record); err != nil {
 			r.logger.Warn("failed to unmarshal execution record from etcd", "key", string(kv.Key), "error", err)
 			continue
 		}
+		record.Revision = kv.ModRevision
 		records = append(records, &record)
 	}
-	span.SetAttributes(attribute.Int("records_returned", len(records)))
-	return records, nil
+
+	var next string
+	if resp.More && len(resp.Kvs) > 0 {
+		last := strings.TrimPrefix(string(resp.Kvs[len(resp.Kvs)-1].Key), prefix)
+		next = base64.RawURLEncoding.EncodeToString([]byte(last))
+	}
+	span.SetAttributes(attribute.Int("records_returned", len(records)), attribute.Bool("has_more", next != ""))
+	return records, next, nil
+}
+
+const (
+	// maxTxnOps is etcd's default limit of operations per transaction.
+	maxTxnOps = 128
+	// maxTxnRecords keeps a delete transaction, of up to five keys per record, within maxTxnOps.
+	maxTxnRecords = maxTxnOps / 5
+	// maxDeleteAttempts is how many times Delete retries a batch whose records were saved
+	// again between reading their index entries and deleting them.
+	maxDeleteAttempts = 3
+)
+
+// Delete removes the records, their index entries and their query index keys,
+// maxTxnRecords at a time.
+func (r *etcdExecutionRepository) Delete(ctx context.Context, jobName string, executionIDs []string) (int, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.etcd.DeleteExecutions")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", jobName), attribute.Int("records", len(executionIDs)))
+
+	deleted := 0
+	for len(executionIDs) > 0 {
+		chunk := executionIDs[:min(maxTxnRecords, len(executionIDs))]
+		executionIDs = executionIDs[len(chunk):]
+
+		for attempt := 1; ; attempt++ {
+			n, ok, err := r.deleteChunk(ctx, jobName, chunk)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "failed to delete execution records from etcd")
+				return deleted, err
+			}
+			if ok {
+				deleted += n
+				break
+			}
+			if attempt == maxDeleteAttempts {
+				span.SetStatus(codes.Error, "execution records kept changing while being deleted")
+				return deleted, fmt.Errorf("failed to delete execution records of job %s from etcd: %w", jobName, domain.ErrExecutionConflict)
+			}
+		}
+	}
+	return deleted, nil
+}
+
+// deleteChunk deletes the records of chunk with their index entries and query index keys.
+// The query index keys to delete are the ones the index entries name, so the delete is
+// guarded on the entries it read, and it reports false if a Save changed one in between.
+func (r *etcdExecutionRepository) deleteChunk(ctx context.Context, jobName string, chunk []string) (int, bool, error) {
+	gets := make([]clientv3.Op, len(chunk))
+	for i, id := range chunk {
+		gets[i] = clientv3.OpGet(historyIndexKey(jobName, id))
+	}
+	resp, err := r.client.Txn(ctx).Then(gets...).Commit()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to look up execution records of job %s in etcd: %w", jobName, err)
+	}
+
+	cmps := make([]clientv3.Cmp, 0, len(chunk))
+	deletes := make([]clientv3.Op, 0, 5*len(chunk))
+	var recordDeletes []int // Positions of the record keys in deletes
+	for i, id := range chunk {
+		indexKey := historyIndexKey(jobName, id)
+		deletes = append(deletes, clientv3.OpDelete(indexKey))
+		kvs := resp.Responses[i].GetResponseRange().GetKvs()
+		if len(kvs) == 0 {
+			cmps = append(cmps, clientv3.Compare(clientv3.ModRevision(indexKey), "=", 0))
+			continue
+		}
+		cmps = append(cmps, clientv3.Compare(clientv3.ModRevision(indexKey), "=", kvs[0].ModRevision))
+		entry, err := decodeIndexEntry(kvs[0].Value)
+		if err != nil {
+			r.logger.Warn("failed to decode execution index entry", "key", string(kvs[0].Key), "error", err)
+			continue
+		}
+		recordDeletes = append(recordDeletes, len(deletes))
+		deletes = append(deletes, clientv3.OpDelete(historyPrefix(jobName)+entry.Seq))
+		deletes = append(deletes, queryIndexDeletes(entry)...)
+	}
+	delResp, err := r.client.Txn(ctx).If(cmps...).Then(deletes...).Commit()
+	if err != nil {
+		return 0, false, fmt.Errorf("failed to delete execution records of job %s from etcd: %w", jobName, err)
+	}
+	if !delResp.Succeeded {
+		return 0, false, nil
+	}
+	deleted := 0
+	for _, i := range recordDeletes {
+		deleted += int(delResp.Responses[i].GetResponseDeleteRange().GetDeleted())
+	}
+	return deleted, true, nil
+}
+
+// ListJobNames returns the names of all jobs that have execution records. It skips from
+// one job's history to the next, reading a single key per job.
+func (r *etcdExecutionRepository) ListJobNames(ctx context.Context) ([]string, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.etcd.ListExecutionJobNames")
+	defer span.End()
+
+	var names []string
+	start, end := ExecutionHistoryDir, clientv3.GetPrefixRangeEnd(ExecutionHistoryDir)
+	for {
+		resp, err := r.client.Get(ctx, start, clientv3.WithRange(end), clientv3.WithKeysOnly(), clientv3.WithLimit(1))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to list execution history from etcd")
+			return nil, fmt.Errorf("failed to list jobs with execution records from etcd: %w", err)
+		}
+		if len(resp.Kvs) == 0 {
+			break
+		}
+		name, _, _ := strings.Cut(strings.TrimPrefix(string(resp.Kvs[0].Key), ExecutionHistoryDir), "/")
+		names = append(names, name)
+		start = clientv3.GetPrefixRangeEnd(historyPrefix(name))
+	}
+	span.SetAttributes(attribute.Int("jobs_returned", len(names)))
+	return names, nil
 }
@@ -0,0 +1,167 @@
+package etcd
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"distributed-cron/internal/domain"
+)
+
+func newTestExecutionRepository(t *testing.T) (*etcdExecutionRepository, *fakeKV) {
+	t.Helper()
+	client, kv := newFakeClient()
+	return NewEtcdExecutionRepository(client, slog.New(slog.DiscardHandler)).(*etcdExecutionRepository), kv
+}
+
+func TestHistorySeqOrder(t *testing.T) {
+	base := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	records := []*domain.ExecutionRecord{
+		{ID: "b", StartTime: base},
+		{ID: "old", StartTime: base.Add(-time.Hour)},
+		{ID: "new", StartTime: base.Add(time.Nanosecond)},
+		{ID: "a", StartTime: base},
+		{ID: "epoch", StartTime: time.Unix(0, 0)},
+	}
+
+	seqs := make([]string, len(records))
+	ids := make(map[string]string, len(records))
+	for i, record := range records {
+		seqs[i] = historySeq(record)
+		ids[seqs[i]] = record.ID
+	}
+	sort.Strings(seqs)
+
+	var got []string
+	for _, seq := range seqs {
+		got = append(got, ids[seq])
+	}
+	// Newest first; records that started in the same nanosecond sort by ID.
+	if want := []string{"new", "a", "b", "old", "epoch"}; !slices.Equal(got, want) {
+		t.Errorf("key order = %v, want %v", got, want)
+	}
+}
+
+func TestSeqTimeBound(t *testing.T) {
+	at := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	bound := seqTime(at)
+	tests := []struct {
+		start     time.Time
+		wantAfter bool // Whether the key sorts at or after the bound
+	}{
+		{start: at.Add(-time.Nanosecond), wantAfter: true},
+		{start: at, wantAfter: true},
+		{start: at.Add(time.Nanosecond), wantAfter: false},
+	}
+	for _, tt := range tests {
+		seq := historySeq(&domain.ExecutionRecord{ID: "x", StartTime: tt.start})
+		if got := seq >= bound; got != tt.wantAfter {
+			t.Errorf("historySeq(%v) >= seqTime(%v) = %v, want %v", tt.start, at, got, tt.wantAfter)
+		}
+	}
+}
+
+func TestIsHistorySeq(t *testing.T) {
+	tests := []struct {
+		suffix string
+		want   bool
+	}{
+		{suffix: historySeq(&domain.ExecutionRecord{ID: "8c4f0e1a-93c1-4d3a-8c53-0c6a1d0e6f2b", StartTime: time.Now()}), want: true},
+		{suffix: "7431264897102417089-b", want: true},
+		{suffix: "8c4f0e1a-93c1-4d3a-8c53-0c6a1d0e6f2b", want: false}, // A bare execution ID
+		{suffix: "7431264897102417089", want: false},
+		{suffix: "743126489710241708-b", want: false},
+		{suffix: "74312648971024170x9-b", want: false},
+		{suffix: "", want: false},
+	}
+	for _, tt := range tests {
+		if got := isHistorySeq(tt.suffix); got != tt.want {
+			t.Errorf("isHistorySeq(%q) = %v, want %v", tt.suffix, got, tt.want)
+		}
+	}
+}
+
+func TestListByJobNamePages(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newTestExecutionRepository(t)
+	base := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	for i, id := range []string{"e1", "e2", "e3", "e4", "e5"} {
+		record := &domain.ExecutionRecord{ID: id, JobName: "report", Status: domain.ExecutionStatusSuccess, StartTime: base.Add(time.Duration(i) * time.Minute)}
+		if err := repo.Save(ctx, record); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	if err := repo.Save(ctx, &domain.ExecutionRecord{ID: "other", JobName: "report-2", StartTime: base}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	var pages [][]string
+	cursor := ""
+	for {
+		records, next, err := repo.ListByJobName(ctx, "report", cursor, 2)
+		if err != nil {
+			t.Fatalf("ListByJobName(%q) error = %v", cursor, err)
+		}
+		var page []string
+		for _, record := range records {
+			page = append(page, record.ID)
+		}
+		pages = append(pages, page)
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	want := [][]string{{"e5", "e4"}, {"e3", "e2"}, {"e1"}}
+	if !slices.EqualFunc(pages, want, slices.Equal[[]string]) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+}
+
+func TestListByJobNameInvalidCursor(t *testing.T) {
+	repo, _ := newTestExecutionRepository(t)
+	for _, cursor := range []string{"not base64!", "%%"} {
+		if _, _, err := repo.ListByJobName(context.Background(), "report", cursor, 10); !errors.Is(err, domain.ErrInvalidCursor) {
+			t.Errorf("ListByJobName(%q) error = %v, want ErrInvalidCursor", cursor, err)
+		}
+	}
+}
+
+func TestSaveKeepsKeyAndRejectsStaleRevision(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := newTestExecutionRepository(t)
+	start := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	record := &domain.ExecutionRecord{ID: "e1", JobName: "report", Status: domain.ExecutionStatusRunning, StartTime: start}
+	if err := repo.Save(ctx, record); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	stale, err := repo.Get(ctx, "report", "e1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	current := *stale
+	current.Status = domain.ExecutionStatusSuccess
+	current.StartTime = start.Add(time.Hour) // A later save must not move the record
+	if err := repo.Save(ctx, &current); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	stale.Status = domain.ExecutionStatusFailed
+	if err := repo.Save(ctx, stale); !errors.Is(err, domain.ErrExecutionConflict) {
+		t.Fatalf("Save() of a stale record error = %v, want ErrExecutionConflict", err)
+	}
+
+	records, _, err := repo.ListByJobName(ctx, "report", "", 10)
+	if err != nil {
+		t.Fatalf("ListByJobName() error = %v", err)
+	}
+	if len(records) != 1 || records[0].Status != domain.ExecutionStatusSuccess {
+		t.Fatalf("ListByJobName() = %+v, want the one record saved last", records)
+	}
+}
@@ -0,0 +1,92 @@
+// internal/infra/etcd/etcd_fire_time_repository.go
+package etcd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path"
+	"time"
+
+	"distributed-cron/internal/domain"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// FireTimeDir holds the last scheduled fire time of every job, keyed by job name.
+	FireTimeDir = "/cron/schedule/"
+)
+
+type etcdFireTimeRepository struct {
+	client *clientv3.Client
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+// NewEtcdFireTimeRepository creates a new repository for job fire times backed by etcd.
+func NewEtcdFireTimeRepository(client *clientv3.Client, logger *slog.Logger) domain.FireTimeRepository {
+	return &etcdFireTimeRepository{
+		client: client,
+		logger: logger,
+		tracer: otel.Tracer("distributed-cron-etcd-fire-time-repo"),
+	}
+}
+
+// SaveLastFireTime stores the fire time under /cron/schedule/{jobName} as an RFC 3339 string.
+func (r *etcdFireTimeRepository) SaveLastFireTime(ctx context.Context, jobName string, firedAt time.Time) error {
+	ctx, span := r.tracer.Start(ctx, "repo.etcd.SaveLastFireTime")
+	defer span.End()
+
+	key := path.Join(FireTimeDir, jobName)
+	span.SetAttributes(attribute.String("job.name", jobName), attribute.String("etcd.key", key))
+
+	_, err := r.client.Put(ctx, key, firedAt.UTC().Format(time.RFC3339Nano))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to put fire time to etcd")
+		return fmt.Errorf("failed to save fire time of job %s to etcd: %w", jobName, err)
+	}
+	return nil
+}
+
+// GetLastFireTime retrieves the last fire time of a job, or the zero time if none is stored.
+func (r *etcdFireTimeRepository) GetLastFireTime(ctx context.Context, jobName string) (time.Time, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.etcd.GetLastFireTime")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", jobName))
+
+	resp, err := r.client.Get(ctx, path.Join(FireTimeDir, jobName))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to get fire time from etcd")
+		return time.Time{}, fmt.Errorf("failed to get fire time of job %s from etcd: %w", jobName, err)
+	}
+	if len(resp.Kvs) == 0 {
+		return time.Time{}, nil
+	}
+
+	firedAt, err := time.Parse(time.RFC3339Nano, string(resp.Kvs[0].Value))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse fire time of job %s: %w", jobName, err)
+	}
+	return firedAt, nil
+}
+
+// DeleteLastFireTime removes the stored fire time of a job.
+func (r *etcdFireTimeRepository) DeleteLastFireTime(ctx context.Context, jobName string) error {
+	ctx, span := r.tracer.Start(ctx, "repo.etcd.DeleteLastFireTime")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", jobName))
+
+	if _, err := r.client.Delete(ctx, path.Join(FireTimeDir, jobName)); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to delete fire time from etcd")
+		return fmt.Errorf("failed to delete fire time of job %s from etcd: %w", jobName, err)
+	}
+	return nil
+}
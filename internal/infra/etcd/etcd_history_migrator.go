@@ -0,0 +1,313 @@
+// internal/infra/etcd/etcd_history_migrator.go
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+
+	"distributed-cron/internal/domain"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HistoryMigrationDir records the history migrations that have completed, one key each.
+const HistoryMigrationDir = "/cron/migrations/history/"
+
+// migrationBatchSize is how many history keys a migration reads at a time.
+const migrationBatchSize = 500
+
+// historyMigration brings the execution history written by an earlier version up to the
+// current layout. migrate returns how many records it changed and must be idempotent, since
+// it is run again if it fails part way.
+type historyMigration struct {
+	name    string
+	migrate func(ctx context.Context) (int, error)
+}
+
+// HistoryMigrator runs the history migrations that have not completed yet. It runs on the
+// leader only, as a LeaderTask, so a single master migrates at a time.
+type HistoryMigrator struct {
+	client *clientv3.Client
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+// NewEtcdHistoryMigrator creates a new HistoryMigrator.
+func NewEtcdHistoryMigrator(client *clientv3.Client, logger *slog.Logger) *HistoryMigrator {
+	return &HistoryMigrator{
+		client: client,
+		logger: logger.With("component", "history-migrator"),
+		tracer: otel.Tracer("distributed-cron-etcd-history-migrator"),
+	}
+}
+
+func (m *HistoryMigrator) migrations() []historyMigration {
+	return []historyMigration{
+		{name: "time-ordered-keys", migrate: m.migrateLegacyKeys},
+		{name: "query-index", migrate: m.backfillQueryIndex},
+	}
+}
+
+// Run applies the pending migrations in order and returns. A migration that fails is
+// retried in the next leadership term, and the migrations after it wait for it.
+func (m *HistoryMigrator) Run(ctx context.Context) {
+	for _, migration := range m.migrations() {
+		if err := m.run(ctx, migration); err != nil {
+			m.logger.Error("failed to migrate execution history", "migration", migration.name, "error", err)
+			return
+		}
+	}
+}
+
+func (m *HistoryMigrator) run(ctx context.Context, migration historyMigration) error {
+	ctx, span := m.tracer.Start(ctx, "repo.etcd.MigrateHistory", trace.WithAttributes(attribute.String("migration", migration.name)))
+	defer span.End()
+
+	doneKey := HistoryMigrationDir + migration.name
+	resp, err := m.client.Get(ctx, doneKey, clientv3.WithCountOnly())
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to get migration state from etcd")
+		return fmt.Errorf("failed to get state of migration %s from etcd: %w", migration.name, err)
+	}
+	if resp.Count > 0 {
+		return nil
+	}
+
+	m.logger.Info("migrating execution history", "migration", migration.name)
+	migrated, err := migration.migrate(ctx)
+	span.SetAttributes(attribute.Int("records_migrated", migrated))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to migrate execution history")
+		return err
+	}
+	if _, err := m.client.Put(ctx, doneKey, "done"); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to put migration state to etcd")
+		return fmt.Errorf("failed to record completion of migration %s in etcd: %w", migration.name, err)
+	}
+	m.logger.Info("migrated execution history", "migration", migration.name, "records_migrated", migrated)
+	return nil
+}
+
+// scanHistory calls fn with every key under ExecutionHistoryDir, in order, reading
+// migrationBatchSize keys at a time.
+func (m *HistoryMigrator) scanHistory(ctx context.Context, fn func(key string) error) error {
+	start, end := ExecutionHistoryDir, clientv3.GetPrefixRangeEnd(ExecutionHistoryDir)
+	for {
+		resp, err := m.client.Get(ctx, start, clientv3.WithRange(end), clientv3.WithKeysOnly(), clientv3.WithLimit(migrationBatchSize))
+		if err != nil {
+			return fmt.Errorf("failed to list execution history from etcd: %w", err)
+		}
+		for _, kv := range resp.Kvs {
+			if err := fn(string(kv.Key)); err != nil {
+				return err
+			}
+		}
+		if !resp.More || len(resp.Kvs) == 0 {
+			return nil
+		}
+		start = string(resp.Kvs[len(resp.Kvs)-1].Key) + "\x00"
+	}
+}
+
+// isHistorySeq reports whether the last segment of a history key is a seq, as written
+// by historySeq, rather than a bare execution ID.
+func isHistorySeq(suffix string) bool {
+	const timeDigits = 19
+	if len(suffix) <= timeDigits || suffix[timeDigits] != '-' {
+		return false
+	}
+	for _, c := range suffix[:timeDigits] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// migrateLegacyKeys moves the records saved under /cron/history/{jobName}/{executionID},
+// the layout before time-ordered keys, to the key historySeq gives them, and writes their
+// index entry and query index keys.
+func (m *HistoryMigrator) migrateLegacyKeys(ctx context.Context) (int, error) {
+	migrated := 0
+	err := m.scanHistory(ctx, func(key string) error {
+		if _, suffix := path.Split(key); isHistorySeq(suffix) {
+			return nil
+		}
+		moved, err := m.migrateLegacyKey(ctx, key)
+		if moved {
+			migrated++
+		}
+		return err
+	})
+	return migrated, err
+}
+
+func (m *HistoryMigrator) migrateLegacyKey(ctx context.Context, key string) (bool, error) {
+	resp, err := m.client.Get(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to get execution record %s from etcd: %w", key, err)
+	}
+	if len(resp.Kvs) == 0 {
+		return false, nil
+	}
+	var record domain.ExecutionRecord
+	if err := json.Unmarshal(resp.Kvs[0].Value, &record); err != nil {
+		m.logger.Warn("failed to unmarshal execution record, leaving it in place", "key", key, "error", err)
+		return false, nil
+	}
+
+	entry := historyIndexEntry{Seq: historySeq(&record), Status: record.Status, WorkerID: record.WorkerID}
+	entryJSON, err := json.Marshal(entry)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal execution index entry %s to JSON: %w", record.ID, err)
+	}
+	queryOps, err := queryIndexOps(record.JobName, nil, &entry)
+	if err != nil {
+		return false, fmt.Errorf("failed to build query index of execution record %s: %w", record.ID, err)
+	}
+	indexKey := historyIndexKey(record.JobName, record.ID)
+	ops := []clientv3.Op{
+		clientv3.OpPut(historyPrefix(record.JobName)+entry.Seq, string(resp.Kvs[0].Value)),
+		clientv3.OpPut(indexKey, string(entryJSON)),
+		clientv3.OpDelete(key),
+	}
+
+	// A record that was saved again in the current layout already has an index entry;
+	// the legacy copy is stale then.
+	txnResp, err := m.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.CreateRevision(indexKey), "=", 0)).
+		Then(append(ops, queryOps...)...).
+		Else(clientv3.OpDelete(key)).
+		Commit()
+	if err != nil {
+		return false, fmt.Errorf("failed to migrate execution record %s in etcd: %w", key, err)
+	}
+	return txnResp.Succeeded, nil
+}
+
+// backfillQueryIndex writes the query index keys of the records saved in the current layout
+// before the query index existed. Their index entry has no status, as the entry only
+// started to record the status and worker the query index keys are written for then.
+func (m *HistoryMigrator) backfillQueryIndex(ctx context.Context) (int, error) {
+	migrated := 0
+	var batch []string
+	flush := func() error {
+		n, err := m.backfillQueryIndexBatch(ctx, batch)
+		migrated += n
+		batch = batch[:0]
+		return err
+	}
+	err := m.scanHistory(ctx, func(key string) error {
+		if _, suffix := path.Split(key); !isHistorySeq(suffix) {
+			return nil
+		}
+		if batch = append(batch, key); len(batch) == maxTxnOps {
+			return flush()
+		}
+		return nil
+	})
+	if err == nil && len(batch) > 0 {
+		err = flush()
+	}
+	return migrated, err
+}
+
+// backfillQueryIndexBatch looks up the index entries of the record keys in one
+// transaction and backfills the records whose entry has no status.
+func (m *HistoryMigrator) backfillQueryIndexBatch(ctx context.Context, keys []string) (int, error) {
+	gets := make([]clientv3.Op, len(keys))
+	for i, key := range keys {
+		jobName, seq := splitHistoryKey(key)
+		_, executionID, _ := strings.Cut(seq, "-")
+		gets[i] = clientv3.OpGet(historyIndexKey(jobName, executionID))
+	}
+	resp, err := m.client.Txn(ctx).Then(gets...).Commit()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get execution index entries from etcd: %w", err)
+	}
+
+	migrated := 0
+	for i, key := range keys {
+		kvs := resp.Responses[i].GetResponseRange().GetKvs()
+		if len(kvs) == 0 {
+			continue
+		}
+		entry, err := decodeIndexEntry(kvs[0].Value)
+		if err != nil {
+			m.logger.Warn("failed to decode execution index entry, leaving it in place", "key", string(kvs[0].Key), "error", err)
+			continue
+		}
+		if entry.Status != "" {
+			continue
+		}
+		backfilled, err := m.backfillQueryIndexKey(ctx, key, string(kvs[0].Key), kvs[0].ModRevision)
+		if backfilled {
+			migrated++
+		}
+		if err != nil {
+			return migrated, err
+		}
+	}
+	return migrated, nil
+}
+
+// backfillQueryIndexKey writes the query index keys of the record at key, and the status
+// and worker they are written for into its index entry. The record is put again with the
+// entry so both keep the same mod revision, which is the record's Revision. The write is
+// guarded on the entry read before, so a record saved in between is left to that Save.
+func (m *HistoryMigrator) backfillQueryIndexKey(ctx context.Context, key, indexKey string, indexRev int64) (bool, error) {
+	resp, err := m.client.Get(ctx, key)
+	if err != nil {
+		return false, fmt.Errorf("failed to get execution record %s from etcd: %w", key, err)
+	}
+	if len(resp.Kvs) == 0 {
+		return false, nil
+	}
+	var record domain.ExecutionRecord
+	if err := json.Unmarshal(resp.Kvs[0].Value, &record); err != nil {
+		m.logger.Warn("failed to unmarshal execution record, leaving it unindexed", "key", key, "error", err)
+		return false, nil
+	}
+
+	jobName, seq := splitHistoryKey(key)
+	entry := historyIndexEntry{Seq: seq, Status: record.Status, WorkerID: record.WorkerID}
+	entryJSON, err := json.Marshal(entry)
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal execution index entry %s to JSON: %w", record.ID, err)
+	}
+	queryOps, err := queryIndexOps(jobName, nil, &entry)
+	if err != nil {
+		return false, fmt.Errorf("failed to build query index of execution record %s: %w", record.ID, err)
+	}
+	ops := []clientv3.Op{
+		clientv3.OpPut(key, string(resp.Kvs[0].Value)),
+		clientv3.OpPut(indexKey, string(entryJSON)),
+	}
+
+	txnResp, err := m.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.ModRevision(indexKey), "=", indexRev)).
+		Then(append(ops, queryOps...)...).
+		Commit()
+	if err != nil {
+		return false, fmt.Errorf("failed to backfill query index of execution record %s in etcd: %w", key, err)
+	}
+	return txnResp.Succeeded, nil
+}
+
+// splitHistoryKey splits a key under ExecutionHistoryDir into the job name and the seq.
+func splitHistoryKey(key string) (jobName, seq string) {
+	rest := strings.TrimPrefix(key, ExecutionHistoryDir)
+	i := strings.LastIndex(rest, "/")
+	return rest[:i], rest[i+1:]
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"log/slog"
 	"path"
+	"strings"
+	"time"
 
 	"distributed-cron/internal/domain"
 	clientv3 "go.etcd.io/etcd/client/v3"
@@ -35,6 +37,15 @@ func NewEtcdJobRepository(client *clientv3.Client, logger *slog.Logger) domain.J
 	}
 }
 
+// NewEtcdJobWatcher creates a watcher for job definitions stored under JobSaveDir.
+func NewEtcdJobWatcher(client *clientv3.Client, logger *slog.Logger) domain.JobWatcher {
+	return &etcdJobRepository{
+		client: client,
+		logger: logger.With("component", "job-watcher"),
+		tracer: otel.Tracer("distributed-cron-etcd-repo"),
+	}
+}
+
 // Save persists the Job struct to etcd.
 func (r *etcdJobRepository) Save(ctx context.Context, job *domain.Job) error {
 	ctx, span := r.tracer.Start(ctx, "repo.etcd.Save")
@@ -125,4 +136,97 @@ func (r *etcdJobRepository) List(ctx context.Context) ([]*domain.Job, error) {
 		jobs = append(jobs, &job)
 	}
 	return jobs, nil
-}
\ No newline at end of file
+}
+
+// jobWatchRetryDelay is how long Watch waits before listing the jobs again after the
+// list or the watch failed.
+const jobWatchRetryDelay = time.Second
+
+// Watch lists the jobs under JobSaveDir and then watches the prefix from the revision of
+// that list, so no change made in between is missed or applied out of order. When the
+// watch fails or its channel closes, e.g. after a compaction, it lists and watches again.
+func (r *etcdJobRepository) Watch(ctx context.Context) <-chan domain.JobEvent {
+	events := make(chan domain.JobEvent)
+
+	go func() {
+		defer close(events)
+		for {
+			if rev, ok := r.syncJobs(ctx, events); ok {
+				r.watchJobs(ctx, rev, events)
+			}
+			select {
+			case <-time.After(jobWatchRetryDelay):
+				r.logger.Info("re-listing jobs to restart the job watch")
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// syncJobs emits a JobEventSync with every stored job and returns the revision of the list.
+func (r *etcdJobRepository) syncJobs(ctx context.Context, events chan<- domain.JobEvent) (int64, bool) {
+	resp, err := r.client.Get(ctx, JobSaveDir, clientv3.WithPrefix())
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Error("failed to list jobs for the job watch", "error", err)
+		}
+		return 0, false
+	}
+
+	jobs := make([]*domain.Job, 0, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		var job domain.Job
+		if err := json.Unmarshal(kv.Value, &job); err != nil {
+			r.logger.Warn("failed to unmarshal listed job", "key", string(kv.Key), "error", err)
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+
+	select {
+	case events <- domain.JobEvent{Type: domain.JobEventSync, Jobs: jobs}:
+		return resp.Header.Revision, true
+	case <-ctx.Done():
+		return 0, false
+	}
+}
+
+// watchJobs emits the changes made after rev until the watch fails or ctx is cancelled.
+func (r *etcdJobRepository) watchJobs(ctx context.Context, rev int64, events chan<- domain.JobEvent) {
+	watchCtx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+	defer cancel()
+
+	watchChan := r.client.Watch(watchCtx, JobSaveDir, clientv3.WithPrefix(), clientv3.WithRev(rev+1))
+	for watchResp := range watchChan {
+		if err := watchResp.Err(); err != nil {
+			r.logger.Error("job watch returned an error", "error", err)
+			return
+		}
+		for _, ev := range watchResp.Events {
+			event := domain.JobEvent{Name: strings.TrimPrefix(string(ev.Kv.Key), JobSaveDir)}
+			switch ev.Type {
+			case clientv3.EventTypePut:
+				var job domain.Job
+				if err := json.Unmarshal(ev.Kv.Value, &job); err != nil {
+					r.logger.Warn("failed to unmarshal watched job", "key", string(ev.Kv.Key), "error", err)
+					continue
+				}
+				event.Type = domain.JobEventPut
+				event.Job = &job
+			case clientv3.EventTypeDelete:
+				event.Type = domain.JobEventDelete
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+	if ctx.Err() == nil {
+		r.logger.Warn("job watch channel closed")
+	}
+}
@@ -3,6 +3,8 @@ package etcd
 import (
 	"context"
 	"distributed-cron/internal/domain"
+	"encoding/json"
+	"fmt"
 	"log/slog"
 	"sync"
 	"time"
@@ -16,23 +18,46 @@ const (
 )
 
 type etcdLeaderElectionManager struct {
-	client   *clientv3.Client
-	session  *concurrency.Session
-	election *concurrency.Election
-	isLeader bool
-	mutex    sync.RWMutex
-	nodeID   string // The ID of the current node
-	ttl      time.Duration
-	logger   *slog.Logger
+	client    *clientv3.Client
+	session   *concurrency.Session
+	election  *concurrency.Election
+	isLeader  bool
+	mutex     sync.RWMutex
+	nodeID    string // The ID of the current node
+	apiAddr   string // Where other masters reach the HTTP API of this node
+	storageID string // ID of the SQLite database of this node; empty with etcd storage
+	ttl       time.Duration
+	logger    *slog.Logger
+}
+
+// candidateValue is the value of a master's election key.
+type candidateValue struct {
+	NodeID    string `json:"node_id"`
+	APIAddr   string `json:"api_addr,omitempty"`
+	StorageID string `json:"storage_id,omitempty"`
+}
+
+// decodeCandidate reads an election value; masters of earlier versions campaign with
+// their bare node ID.
+func decodeCandidate(value []byte) candidateValue {
+	var candidate candidateValue
+	if err := json.Unmarshal(value, &candidate); err != nil || candidate.NodeID == "" {
+		return candidateValue{NodeID: string(value)}
+	}
+	return candidate
 }
 
 // NewEtcdLeaderElectionManager creates a manager for leader election using etcd.
-func NewEtcdLeaderElectionManager(client *clientv3.Client, nodeID string, ttl time.Duration, logger *slog.Logger) domain.LeaderElectionManager {
+// apiAddr is published with the candidacy, so followers can forward requests to the leader,
+// and so is storageID, so masters and workers can check they share the SQLite database.
+func NewEtcdLeaderElectionManager(client *clientv3.Client, nodeID, apiAddr, storageID string, ttl time.Duration, logger *slog.Logger) domain.LeaderElectionManager {
 	return &etcdLeaderElectionManager{
-		client: client,
-		nodeID: nodeID,
-		ttl:    ttl,
-		logger: logger.With("component", "leader-election"),
+		client:    client,
+		nodeID:    nodeID,
+		apiAddr:   apiAddr,
+		storageID: storageID,
+		ttl:       ttl,
+		logger:    logger.With("component", "leader-election"),
 	}
 }
 
@@ -48,7 +73,11 @@ func (m *etcdLeaderElectionManager) Campaign(ctx context.Context) (<-chan struct
 	m.election = concurrency.NewElection(m.session, LeaderElectionKey)
 
 	// Campaign blocks until this node becomes the leader or the context is canceled.
-	if err := m.election.Campaign(ctx, m.nodeID); err != nil {
+	value, err := json.Marshal(candidateValue{NodeID: m.nodeID, APIAddr: m.apiAddr, StorageID: m.storageID})
+	if err != nil {
+		return nil, err
+	}
+	if err := m.election.Campaign(ctx, string(value)); err != nil {
 		return nil, err
 	}
 
@@ -58,7 +87,14 @@ func (m *etcdLeaderElectionManager) Campaign(ctx context.Context) (<-chan struct
 	m.mutex.Unlock()
 
 	// The returned channel is closed if the session expires, meaning leadership is lost.
-	return m.session.Done(), nil
+	done := m.session.Done()
+	go func() {
+		<-done
+		m.mutex.Lock()
+		m.isLeader = false
+		m.mutex.Unlock()
+	}()
+	return done, nil
 }
 
 func (m *etcdLeaderElectionManager) Resign(ctx context.Context) error {
@@ -78,3 +114,39 @@ func (m *etcdLeaderElectionManager) IsLeader() bool {
 	defer m.mutex.Unlock()
 	return m.isLeader
 }
+
+type etcdClusterObserver struct {
+	client *clientv3.Client
+}
+
+// NewEtcdClusterObserver creates an observer of the leader election that does not take part in it.
+func NewEtcdClusterObserver(client *clientv3.Client) domain.ClusterObserver {
+	return &etcdClusterObserver{client: client}
+}
+
+// Candidates lists the keys of the election. Each campaigning master holds one key,
+// and the one created first is the leader. The leases of the candidates are looked up
+// concurrently; a candidate whose lease cannot be looked up is reported with a zero TTL.
+func (o *etcdClusterObserver) Candidates(ctx context.Context) ([]domain.ClusterMember, error) {
+	resp, err := o.client.Get(ctx, LeaderElectionKey+"/", clientv3.WithPrefix(),
+		clientv3.WithSort(clientv3.SortByCreateRevision, clientv3.SortAscend))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list election candidates: %w", err)
+	}
+
+	members := make([]domain.ClusterMember, len(resp.Kvs))
+	var wg sync.WaitGroup
+	for i, kv := range resp.Kvs {
+		candidate := decodeCandidate(kv.Value)
+		members[i] = domain.ClusterMember{NodeID: candidate.NodeID, APIAddr: candidate.APIAddr, StorageID: candidate.StorageID, Leader: i == 0}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ttl, err := o.client.TimeToLive(ctx, clientv3.LeaseID(kv.Lease)); err == nil {
+				members[i].LeaseTTLSeconds = max(ttl.TTL, 0)
+			}
+		}()
+	}
+	wg.Wait()
+	return members, nil
+}
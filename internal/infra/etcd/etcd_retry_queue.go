@@ -0,0 +1,184 @@
+// internal/infra/etcd/etcd_retry_queue.go
+package etcd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path"
+	"time"
+
+	"distributed-cron/internal/domain"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// RetryQueueDir holds pending retry requests, keyed by /cron/retries/{jobName}/{executionID}.
+	RetryQueueDir = "/cron/retries/"
+)
+
+type etcdRetryQueue struct {
+	client *clientv3.Client
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+// NewEtcdRetryQueue creates a retry queue backed by etcd.
+func NewEtcdRetryQueue(client *clientv3.Client, logger *slog.Logger) domain.RetryQueue {
+	return &etcdRetryQueue{
+		client: client,
+		logger: logger.With("component", "retry-queue"),
+		tracer: otel.Tracer("distributed-cron-etcd-retry-queue"),
+	}
+}
+
+func retryKey(jobName, executionID string) string {
+	return path.Join(RetryQueueDir, jobName, executionID)
+}
+
+// Enqueue stores the request; a later request for the same execution replaces it.
+func (q *etcdRetryQueue) Enqueue(ctx context.Context, req *domain.RetryRequest) error {
+	ctx, span := q.tracer.Start(ctx, "repo.etcd.EnqueueRetry")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("job.name", req.JobName),
+		attribute.String("execution.id", req.ExecutionID),
+		attribute.Int("retry.attempt", req.Attempt),
+	)
+
+	reqJSON, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("failed to marshal retry request %s to JSON: %w", req.ExecutionID, err)
+	}
+	if _, err := q.client.Put(ctx, retryKey(req.JobName, req.ExecutionID), string(reqJSON)); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to put retry request to etcd")
+		return fmt.Errorf("failed to enqueue retry of execution %s: %w", req.ExecutionID, err)
+	}
+	return nil
+}
+
+// retryWatchRetryDelay is how long Watch waits before loading the pending requests again
+// after the load or the watch failed.
+const retryWatchRetryDelay = time.Second
+
+// Watch loads the pending requests and then watches for new ones from the revision
+// of that load, so no request enqueued in between is missed. When the watch fails or its
+// channel closes, e.g. after a compaction, it loads and watches again; a request is
+// emitted once per revision it was written at, however often it is loaded.
+func (q *etcdRetryQueue) Watch(ctx context.Context) <-chan *domain.RetryRequest {
+	requests := make(chan *domain.RetryRequest)
+
+	go func() {
+		defer close(requests)
+		emitted := make(map[string]int64) // Mod revision of each request emitted so far, by key
+		for {
+			if rev, ok := q.loadRetries(ctx, requests, emitted); ok {
+				q.watchRetries(ctx, rev, requests, emitted)
+			}
+			select {
+			case <-time.After(retryWatchRetryDelay):
+				q.logger.Info("reloading retry requests to restart the retry queue watch")
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return requests
+}
+
+// emitRetry sends the request stored at key unless it was emitted at the same revision
+// already. It returns false if ctx is cancelled.
+func (q *etcdRetryQueue) emitRetry(ctx context.Context, requests chan<- *domain.RetryRequest, emitted map[string]int64, key string, value []byte, modRev int64) bool {
+	if emitted[key] == modRev {
+		return true
+	}
+	var req domain.RetryRequest
+	if err := json.Unmarshal(value, &req); err != nil {
+		q.logger.Warn("failed to unmarshal retry request", "key", key, "error", err)
+		return true
+	}
+	select {
+	case requests <- &req:
+		emitted[key] = modRev
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// loadRetries emits the pending requests and returns the revision of the load.
+func (q *etcdRetryQueue) loadRetries(ctx context.Context, requests chan<- *domain.RetryRequest, emitted map[string]int64) (int64, bool) {
+	resp, err := q.client.Get(ctx, RetryQueueDir, clientv3.WithPrefix())
+	if err != nil {
+		if ctx.Err() == nil {
+			q.logger.Error("failed to load pending retry requests", "error", err)
+		}
+		return 0, false
+	}
+
+	pending := make(map[string]bool, len(resp.Kvs))
+	for _, kv := range resp.Kvs {
+		pending[string(kv.Key)] = true
+		if !q.emitRetry(ctx, requests, emitted, string(kv.Key), kv.Value, kv.ModRevision) {
+			return 0, false
+		}
+	}
+	for key := range emitted {
+		if !pending[key] {
+			delete(emitted, key)
+		}
+	}
+	return resp.Header.Revision, true
+}
+
+// watchRetries emits the requests enqueued after rev until the watch fails or ctx is cancelled.
+func (q *etcdRetryQueue) watchRetries(ctx context.Context, rev int64, requests chan<- *domain.RetryRequest, emitted map[string]int64) {
+	watchCtx, cancel := context.WithCancel(clientv3.WithRequireLeader(ctx))
+	defer cancel()
+
+	watchChan := q.client.Watch(watchCtx, RetryQueueDir, clientv3.WithPrefix(), clientv3.WithRev(rev+1))
+	for watchResp := range watchChan {
+		if err := watchResp.Err(); err != nil {
+			q.logger.Error("retry queue watch returned an error", "error", err)
+			return
+		}
+		for _, ev := range watchResp.Events {
+			if ev.Type != clientv3.EventTypePut {
+				delete(emitted, string(ev.Kv.Key))
+				continue
+			}
+			if !q.emitRetry(ctx, requests, emitted, string(ev.Kv.Key), ev.Kv.Value, ev.Kv.ModRevision) {
+				return
+			}
+		}
+	}
+	if ctx.Err() == nil {
+		q.logger.Warn("retry queue watch channel closed")
+	}
+}
+
+// Remove deletes the request in a transaction so that only one caller sees it succeed.
+func (q *etcdRetryQueue) Remove(ctx context.Context, req *domain.RetryRequest) (bool, error) {
+	ctx, span := q.tracer.Start(ctx, "repo.etcd.RemoveRetry")
+	defer span.End()
+	span.SetAttributes(attribute.String("execution.id", req.ExecutionID))
+
+	key := retryKey(req.JobName, req.ExecutionID)
+	resp, err := q.client.Txn(ctx).
+		If(clientv3.Compare(clientv3.Version(key), ">", 0)).
+		Then(clientv3.OpDelete(key)).
+		Commit()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to delete retry request from etcd")
+		return false, fmt.Errorf("failed to remove retry of execution %s: %w", req.ExecutionID, err)
+	}
+	return resp.Succeeded, nil
+}
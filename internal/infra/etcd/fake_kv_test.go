@@ -0,0 +1,213 @@
+package etcd
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/mvccpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+)
+
+// fakeKV is an in-memory clientv3.KV with revisions, ranges, limits and transactions
+// with compares, enough for the repositories in this package.
+type fakeKV struct {
+	entries map[string]*fakeEntry
+	rev     int64
+	// beforeCommit, if set, runs once before the next transaction is committed, to
+	// make a concurrent change between a read and the guarded write that follows it.
+	beforeCommit func()
+}
+
+type fakeEntry struct {
+	value          string
+	create, modRev int64
+}
+
+// newFakeClient returns a client whose KV is a new fakeKV.
+func newFakeClient() (*clientv3.Client, *fakeKV) {
+	kv := &fakeKV{entries: map[string]*fakeEntry{}, rev: 1}
+	return &clientv3.Client{KV: kv}, kv
+}
+
+func (f *fakeKV) header() *pb.ResponseHeader { return &pb.ResponseHeader{Revision: f.rev} }
+
+// inRange reports whether key is selected by an op on [start, end).
+func inRange(key, start, end string) bool {
+	switch end {
+	case "":
+		return key == start
+	case "\x00":
+		return key >= start
+	default:
+		return key >= start && key < end
+	}
+}
+
+func (f *fakeKV) get(op clientv3.Op) *pb.RangeResponse {
+	start, end := string(op.KeyBytes()), string(op.RangeBytes())
+	var keys []string
+	for key := range f.entries {
+		if inRange(key, start, end) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	resp := &pb.RangeResponse{Header: f.header(), Count: int64(len(keys))}
+	if op.IsCountOnly() {
+		return resp
+	}
+	if limit := op.Limit(); limit > 0 && int64(len(keys)) > limit {
+		keys, resp.More = keys[:limit], true
+	}
+	for _, key := range keys {
+		e := f.entries[key]
+		kv := &mvccpb.KeyValue{Key: []byte(key), CreateRevision: e.create, ModRevision: e.modRev}
+		if !op.IsKeysOnly() {
+			kv.Value = []byte(e.value)
+		}
+		resp.Kvs = append(resp.Kvs, kv)
+	}
+	return resp
+}
+
+func (f *fakeKV) apply(op clientv3.Op, rev int64) *pb.ResponseOp {
+	switch {
+	case op.IsGet():
+		return &pb.ResponseOp{Response: &pb.ResponseOp_ResponseRange{ResponseRange: f.get(op)}}
+	case op.IsPut():
+		key := string(op.KeyBytes())
+		e := f.entries[key]
+		if e == nil {
+			e = &fakeEntry{create: rev}
+			f.entries[key] = e
+		}
+		e.value, e.modRev = string(op.ValueBytes()), rev
+		return &pb.ResponseOp{Response: &pb.ResponseOp_ResponsePut{ResponsePut: &pb.PutResponse{Header: f.header()}}}
+	default:
+		start, end := string(op.KeyBytes()), string(op.RangeBytes())
+		var deleted int64
+		for key := range f.entries {
+			if inRange(key, start, end) {
+				delete(f.entries, key)
+				deleted++
+			}
+		}
+		return &pb.ResponseOp{Response: &pb.ResponseOp_ResponseDeleteRange{ResponseDeleteRange: &pb.DeleteRangeResponse{Header: f.header(), Deleted: deleted}}}
+	}
+}
+
+func (f *fakeKV) Put(_ context.Context, key, val string, opts ...clientv3.OpOption) (*clientv3.PutResponse, error) {
+	f.rev++
+	return (*clientv3.PutResponse)(f.apply(clientv3.OpPut(key, val, opts...), f.rev).GetResponsePut()), nil
+}
+
+func (f *fakeKV) Get(_ context.Context, key string, opts ...clientv3.OpOption) (*clientv3.GetResponse, error) {
+	return (*clientv3.GetResponse)(f.get(clientv3.OpGet(key, opts...))), nil
+}
+
+func (f *fakeKV) Delete(_ context.Context, key string, opts ...clientv3.OpOption) (*clientv3.DeleteResponse, error) {
+	f.rev++
+	return (*clientv3.DeleteResponse)(f.apply(clientv3.OpDelete(key, opts...), f.rev).GetResponseDeleteRange()), nil
+}
+
+func (f *fakeKV) Compact(context.Context, int64, ...clientv3.CompactOption) (*clientv3.CompactResponse, error) {
+	return &clientv3.CompactResponse{}, nil
+}
+
+func (f *fakeKV) Do(context.Context, clientv3.Op) (clientv3.OpResponse, error) {
+	panic("fakeKV does not support Do")
+}
+
+func (f *fakeKV) Txn(context.Context) clientv3.Txn { return &fakeTxn{kv: f} }
+
+type fakeTxn struct {
+	kv      *fakeKV
+	cmps    []clientv3.Cmp
+	thenOps []clientv3.Op
+	elseOps []clientv3.Op
+}
+
+func (t *fakeTxn) If(cmps ...clientv3.Cmp) clientv3.Txn { t.cmps = cmps; return t }
+func (t *fakeTxn) Then(ops ...clientv3.Op) clientv3.Txn { t.thenOps = ops; return t }
+func (t *fakeTxn) Else(ops ...clientv3.Op) clientv3.Txn { t.elseOps = ops; return t }
+
+func (t *fakeTxn) holds(c clientv3.Cmp) bool {
+	e := t.kv.entries[string(c.Key)]
+	if e == nil {
+		e = &fakeEntry{}
+	}
+	var diff int
+	switch target := c.TargetUnion.(type) {
+	case *pb.Compare_ModRevision:
+		diff = compareInt64(e.modRev, target.ModRevision)
+	case *pb.Compare_CreateRevision:
+		diff = compareInt64(e.create, target.CreateRevision)
+	case *pb.Compare_Version:
+		version := int64(0)
+		if e.create != 0 {
+			version = 1 // Versions past the first are not tracked
+		}
+		diff = compareInt64(version, target.Version)
+	case *pb.Compare_Value:
+		diff = bytes.Compare([]byte(e.value), target.Value)
+	default:
+		panic("fakeTxn does not support this compare target")
+	}
+	switch c.Result {
+	case pb.Compare_EQUAL:
+		return diff == 0
+	case pb.Compare_NOT_EQUAL:
+		return diff != 0
+	case pb.Compare_GREATER:
+		return diff > 0
+	default:
+		return diff < 0
+	}
+}
+
+func compareInt64(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// Commit applies the transaction at one new revision, as etcd does, and enforces
+// etcd's default limit of operations per transaction.
+func (t *fakeTxn) Commit() (*clientv3.TxnResponse, error) {
+	if hook := t.kv.beforeCommit; hook != nil {
+		t.kv.beforeCommit = nil
+		hook()
+	}
+	if len(t.cmps) > maxTxnOps || len(t.thenOps) > maxTxnOps || len(t.elseOps) > maxTxnOps {
+		panic("too many operations in txn request")
+	}
+
+	succeeded := true
+	for _, c := range t.cmps {
+		succeeded = succeeded && t.holds(c)
+	}
+	ops := t.thenOps
+	if !succeeded {
+		ops = t.elseOps
+	}
+	for _, op := range ops {
+		if !op.IsGet() {
+			t.kv.rev++
+			break
+		}
+	}
+
+	resp := &clientv3.TxnResponse{Succeeded: succeeded}
+	for _, op := range ops {
+		resp.Responses = append(resp.Responses, t.kv.apply(op, t.kv.rev))
+	}
+	resp.Header = t.kv.header()
+	return resp, nil
+}
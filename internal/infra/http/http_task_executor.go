@@ -3,84 +3,113 @@ package http
 import (
 	"context"
 	"distributed-cron/internal/domain"
-	"errors"
 	"fmt"
 	"io"
-	"net"
 	"net/http"
+	"slices"
 	"strings"
-	"time"
 )
 
+// maxResponseBody bounds how much of a response body is read for assertions.
+const maxResponseBody = 1 << 20
+
+// maxOutputBody bounds how much of a response body is written to the execution output.
+const maxOutputBody = 1024
+
 type httpTaskExecutor struct {
 	client *http.Client
 }
 
+// NewHttpTaskExecutor creates a new httpTaskExecutor instance.
+// Requests are bounded by the execution context, which carries the job's timeout.
 func NewHttpTaskExecutor() domain.TaskExecutor {
 	return &httpTaskExecutor{
-		client: &http.Client{
-			Timeout: 15 * time.Second,
-		},
+		client: &http.Client{},
 	}
 }
 
-// Execute initiates an HTTP request and retries on failure.
-func (e *httpTaskExecutor) Execute(ctx context.Context, job *domain.Job) (string, error) {
-	if job.RetryPolicy == nil || job.RetryPolicy.MaxRetries == 0 {
-		return e.doExecute(ctx, job)
+// Execute performs a single HTTP request and writes a portion of the response body to output.
+// The response is successful if its status is expected and the body satisfies the job's assertion.
+// Retries are handled by the worker according to the job's retry policy; 4xx responses are
+// reported as domain.ErrNonRetriable.
+func (e *httpTaskExecutor) Execute(ctx context.Context, job *domain.Job, output io.Writer) (*domain.ExecutionResult, error) {
+	req, err := newRequest(ctx, &job.Executor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create http request: %w", err)
 	}
 
-	var lastErr error
-	var output string
-	for i := 0; i <= job.RetryPolicy.MaxRetries; i++ {
-		output, err := e.doExecute(ctx, job)
-		if err == nil {
-			return output, nil
-		}
-
-		lastErr = err
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
 
-		var netErr net.Error
-		if errors.As(err, &netErr) && netErr.Timeout() {
-			// Retriable
-		} else if strings.Contains(err.Error(), "5xx") {
-			// Retriable
-		} else {
-			return output, fmt.Errorf("non-retriable error on attempt %d: %w", i+1, err)
-		}
+	result := &domain.ExecutionResult{HTTPStatus: resp.StatusCode}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	result.OutputBytes = int64(len(body))
+	// Copy a small portion of the body for output logging.
+	output.Write(body[:min(len(body), maxOutputBody)])
+	if err != nil {
+		return result, fmt.Errorf("failed to read http response body: %w", err)
+	}
+	// Count the rest of a large body without keeping it.
+	rest, _ := io.Copy(io.Discard, resp.Body)
+	result.OutputBytes += rest
 
-		if i == job.RetryPolicy.MaxRetries {
-			break
+	if err := checkStatus(resp, job.Executor.ExpectedStatus); err != nil {
+		return result, err
+	}
+	if job.Executor.Assertion != nil {
+		if err := job.Executor.Assertion.Check(body); err != nil {
+			return result, fmt.Errorf("http response assertion failed: %w", err)
 		}
-
-		time.Sleep(job.RetryPolicy.Backoff)
 	}
-
-	return output, fmt.Errorf("job failed after %d retries: %w", job.RetryPolicy.MaxRetries, lastErr)
+	return result, nil
 }
 
-// doExecute performs a single HTTP request execution.
-func (e *httpTaskExecutor) doExecute(ctx context.Context, job *domain.Job) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, job.Executor.Method, job.Executor.URL, nil)
+// newRequest builds the request described by an HTTP executor.
+func newRequest(ctx context.Context, executor *domain.JobExecutor) (*http.Request, error) {
+	var body io.Reader
+	if executor.Body != "" {
+		body = strings.NewReader(executor.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, executor.Method, executor.URL, body)
 	if err != nil {
-		return "", fmt.Errorf("failed to create http request: %w", err)
+		return nil, err
 	}
 
-	resp, err := e.client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("http request failed: %w", err)
+	for name, value := range executor.Headers {
+		req.Header.Set(name, value)
 	}
-	defer resp.Body.Close()
+	if auth := executor.Auth; auth != nil {
+		switch auth.Type {
+		case domain.HTTPAuthBasic:
+			req.SetBasicAuth(auth.Username, auth.Password)
+		case domain.HTTPAuthBearer:
+			req.Header.Set("Authorization", "Bearer "+auth.Token)
+		}
+	}
+	return req, nil
+}
 
-	// Read a small portion of the body for output logging.
-	bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 1024)) // Read max 1KB
+// checkStatus compares the response status with the expected ones, or with 2xx and 3xx if none are set.
+func checkStatus(resp *http.Response, expected []int) error {
+	if len(expected) > 0 {
+		if slices.Contains(expected, resp.StatusCode) {
+			return nil
+		}
+		err := fmt.Errorf("http request returned unexpected status %s, expected one of %v", resp.Status, expected)
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+			return fmt.Errorf("%w: %w", err, domain.ErrNonRetriable)
+		}
+		return err
+	}
 
 	if resp.StatusCode >= 500 {
-		return string(bodyBytes), fmt.Errorf("http request returned 5xx server error: %s", resp.Status)
+		return fmt.Errorf("http request returned 5xx server error: %s", resp.Status)
 	}
 	if resp.StatusCode >= 400 {
-		return string(bodyBytes), fmt.Errorf("http request returned 4xx client error: %s", resp.Status)
+		return fmt.Errorf("http request returned 4xx client error: %s: %w", resp.Status, domain.ErrNonRetriable)
 	}
-
-	return string(bodyBytes), nil
+	return nil
 }
@@ -0,0 +1,112 @@
+// internal/infra/logstore/fs_log_store.go
+package logstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"distributed-cron/internal/domain"
+)
+
+// fsLogStore keeps execution output in files on the local file system,
+// at {dir}/{jobName}/{executionID}.log.
+type fsLogStore struct {
+	dir string
+}
+
+// NewFSLogStore creates a log store under dir, creating the directory if needed.
+func NewFSLogStore(dir string) (domain.LogStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory %s: %w", dir, err)
+	}
+	return &fsLogStore{dir: dir}, nil
+}
+
+// path escapes the names, so that a job name cannot point outside of the store.
+func (s *fsLogStore) path(jobName, executionID string) string {
+	return filepath.Join(s.dir, escapeName(jobName), escapeName(executionID)+".log")
+}
+
+// escapeName escapes name for use as a single path element. url.PathEscape escapes
+// separators but leaves "." and ".." alone.
+func escapeName(name string) string {
+	escaped := url.PathEscape(name)
+	if escaped == "." || escaped == ".." {
+		return strings.ReplaceAll(escaped, ".", "%2E")
+	}
+	return escaped
+}
+
+func (s *fsLogStore) Create(ctx context.Context, jobName, executionID string) (io.WriteCloser, error) {
+	path := s.path(jobName, executionID)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory of job %s: %w", jobName, err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log of execution %s: %w", executionID, err)
+	}
+	return f, nil
+}
+
+func (s *fsLogStore) Open(ctx context.Context, jobName, executionID string) (io.ReadCloser, error) {
+	f, err := os.Open(s.path(jobName, executionID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrLogNotFound, executionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open log of execution %s: %w", executionID, err)
+	}
+	return f, nil
+}
+
+// Prune removes the log files last modified before before. Job directories are kept,
+// since Create may be about to write to them.
+func (s *fsLogStore) Prune(ctx context.Context, before time.Time) (int, error) {
+	jobDirs, err := os.ReadDir(s.dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read log directory %s: %w", s.dir, err)
+	}
+
+	pruned := 0
+	for _, jobDir := range jobDirs {
+		if !jobDir.IsDir() {
+			continue
+		}
+		dir := filepath.Join(s.dir, jobDir.Name())
+		logs, err := os.ReadDir(dir)
+		if err != nil {
+			return pruned, fmt.Errorf("failed to read log directory %s: %w", dir, err)
+		}
+		for _, file := range logs {
+			if ctx.Err() != nil {
+				return pruned, ctx.Err()
+			}
+			if file.IsDir() || filepath.Ext(file.Name()) != ".log" {
+				continue
+			}
+			info, err := file.Info()
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return pruned, fmt.Errorf("failed to stat log %s: %w", file.Name(), err)
+			}
+			if !info.ModTime().Before(before) {
+				continue
+			}
+			if err := os.Remove(filepath.Join(dir, file.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return pruned, fmt.Errorf("failed to delete log %s: %w", file.Name(), err)
+			}
+			pruned++
+		}
+	}
+	return pruned, nil
+}
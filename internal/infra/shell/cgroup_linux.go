@@ -0,0 +1,189 @@
+//go:build linux
+
+// internal/infra/shell/cgroup_linux.go
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"distributed-cron/internal/domain"
+
+	"github.com/google/uuid"
+	"golang.org/x/sys/unix"
+)
+
+// cgroupControllers are the cgroup v2 controllers that enforce domain.ResourceLimits.
+var cgroupControllers = []string{"cpu", "memory", "pids"}
+
+// cpuPeriod is the cgroup CPU accounting period, in microseconds.
+const cpuPeriod = 100000
+
+// cgroupManager creates one cgroup v2 per limited execution under a parent directory.
+type cgroupManager struct {
+	parent string
+
+	setupOnce sync.Once
+	enabled   []string // Controllers enabled for the parent's children
+	setupErr  error
+}
+
+func newCgroupManager(parent string) *cgroupManager {
+	return &cgroupManager{parent: parent}
+}
+
+// setup creates the parent cgroup and enables the controllers for its children.
+// It only runs once; later calls return the first result.
+func (m *cgroupManager) setup() error {
+	m.setupOnce.Do(func() {
+		if m.parent == "" {
+			m.setupErr = errors.New("worker has no cgroup_parent configured")
+			return
+		}
+		if err := os.MkdirAll(m.parent, 0o755); err != nil {
+			m.setupErr = fmt.Errorf("failed to create cgroup parent %s: %w", m.parent, err)
+			return
+		}
+		var fs unix.Statfs_t
+		if err := unix.Statfs(m.parent, &fs); err != nil || fs.Type != unix.CGROUP2_SUPER_MAGIC {
+			m.setupErr = fmt.Errorf("cgroup parent %s is not on a cgroup v2 file system", m.parent)
+			return
+		}
+
+		available, err := os.ReadFile(filepath.Join(m.parent, "cgroup.controllers"))
+		if err != nil {
+			m.setupErr = fmt.Errorf("failed to read controllers of cgroup %s: %w", m.parent, err)
+			return
+		}
+		var enable []string
+		for _, c := range strings.Fields(string(available)) {
+			if slices.Contains(cgroupControllers, c) {
+				m.enabled = append(m.enabled, c)
+				enable = append(enable, "+"+c)
+			}
+		}
+		if len(enable) > 0 {
+			if err := os.WriteFile(filepath.Join(m.parent, "cgroup.subtree_control"), []byte(strings.Join(enable, " ")), 0); err != nil {
+				m.setupErr = fmt.Errorf("failed to enable controllers in cgroup %s: %w", m.parent, err)
+			}
+		}
+	})
+	return m.setupErr
+}
+
+// check returns an error if limits cannot be enforced on this worker.
+func (m *cgroupManager) check(limits *domain.ResourceLimits) error {
+	if err := m.setup(); err != nil {
+		return fmt.Errorf("resource limits are not supported on this worker: %w", err)
+	}
+	for controller, needed := range map[string]bool{
+		"cpu":    limits.CPU > 0,
+		"memory": limits.MemoryMB > 0,
+		"pids":   limits.Pids > 0,
+	} {
+		if needed && !slices.Contains(m.enabled, controller) {
+			return fmt.Errorf("resource limits are not supported on this worker: cgroup controller %s is not available in %s", controller, m.parent)
+		}
+	}
+	return nil
+}
+
+// create makes a new cgroup with the given limits.
+func (m *cgroupManager) create(limits *domain.ResourceLimits) (*cgroup, error) {
+	if err := m.check(limits); err != nil {
+		return nil, err
+	}
+
+	path := filepath.Join(m.parent, "exec-"+uuid.NewString())
+	if err := os.Mkdir(path, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cgroup: %w", err)
+	}
+	cg := &cgroup{path: path}
+
+	var settings [][2]string
+	if limits.CPU > 0 {
+		settings = append(settings, [2]string{"cpu.max", fmt.Sprintf("%d %d", int64(limits.CPU*cpuPeriod), cpuPeriod)})
+	}
+	if limits.MemoryMB > 0 {
+		settings = append(settings, [2]string{"memory.max", strconv.FormatInt(limits.MemoryMB<<20, 10)})
+		// Without swap, exceeding the limit ends in an OOM kill instead of slowing down.
+		if _, err := os.Stat(filepath.Join(path, "memory.swap.max")); err == nil {
+			settings = append(settings, [2]string{"memory.swap.max", "0"})
+		}
+	}
+	if limits.Pids > 0 {
+		settings = append(settings, [2]string{"pids.max", strconv.FormatInt(limits.Pids, 10)})
+	}
+	for _, s := range settings {
+		if err := os.WriteFile(filepath.Join(path, s[0]), []byte(s[1]), 0); err != nil {
+			cg.destroy()
+			return nil, fmt.Errorf("failed to set %s of cgroup: %w", s[0], err)
+		}
+	}
+
+	dir, err := os.Open(path)
+	if err != nil {
+		cg.destroy()
+		return nil, fmt.Errorf("failed to open cgroup: %w", err)
+	}
+	cg.dir = dir
+	return cg, nil
+}
+
+// cgroup is the cgroup of a single execution.
+type cgroup struct {
+	path string
+	dir  *os.File
+}
+
+// attach makes cmd start inside the cgroup, so that no process escapes it.
+func (c *cgroup) attach(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.UseCgroupFD = true
+	cmd.SysProcAttr.CgroupFD = int(c.dir.Fd())
+}
+
+// oomKilled reports whether the kernel killed a process of the cgroup for exceeding memory.max.
+func (c *cgroup) oomKilled() bool {
+	f, err := os.Open(filepath.Join(c.path, "memory.events"))
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if count, ok := strings.CutPrefix(scanner.Text(), "oom_kill "); ok {
+			n, _ := strconv.Atoi(count)
+			return n > 0
+		}
+	}
+	return false
+}
+
+// destroy kills whatever is left in the cgroup and removes it.
+func (c *cgroup) destroy() {
+	if c.dir != nil {
+		c.dir.Close()
+	}
+	// cgroup.kill exists since Linux 5.14.
+	os.WriteFile(filepath.Join(c.path, "cgroup.kill"), []byte("1"), 0)
+	for i := 0; i < 50; i++ {
+		if err := os.Remove(c.path); err == nil || errors.Is(err, os.ErrNotExist) {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
@@ -0,0 +1,34 @@
+//go:build !linux
+
+// internal/infra/shell/cgroup_other.go
+package shell
+
+import (
+	"errors"
+	"os/exec"
+
+	"distributed-cron/internal/domain"
+)
+
+// cgroupManager refuses every job with resource limits, as cgroups are Linux only.
+type cgroupManager struct{}
+
+func newCgroupManager(parent string) *cgroupManager {
+	return &cgroupManager{}
+}
+
+func (m *cgroupManager) check(limits *domain.ResourceLimits) error {
+	return errors.New("resource limits are not supported on this worker: cgroups require linux")
+}
+
+func (m *cgroupManager) create(limits *domain.ResourceLimits) (*cgroup, error) {
+	return nil, m.check(limits)
+}
+
+type cgroup struct{}
+
+func (c *cgroup) attach(cmd *exec.Cmd) {}
+
+func (c *cgroup) oomKilled() bool { return false }
+
+func (c *cgroup) destroy() {}
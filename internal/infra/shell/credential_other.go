@@ -0,0 +1,22 @@
+//go:build !unix
+
+// internal/infra/shell/credential_other.go
+package shell
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// credential is never set on platforms without Unix credentials.
+type credential struct{}
+
+// resolveCredential rejects every run-as request, as switching users is only supported on Unix.
+func resolveCredential(userName, groupName string) (*credential, error) {
+	if userName == "" && groupName == "" {
+		return nil, nil
+	}
+	return nil, errors.New("running jobs as another user is only supported on unix workers")
+}
+
+func applyCredential(cmd *exec.Cmd, cred *credential) {}
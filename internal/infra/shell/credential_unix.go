@@ -0,0 +1,97 @@
+//go:build unix
+
+// internal/infra/shell/credential_unix.go
+package shell
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"strconv"
+	"syscall"
+)
+
+// credential is the identity a command is started with.
+type credential = syscall.Credential
+
+// resolveCredential looks up the user and group a job runs as. It returns nil if the
+// job runs as the worker's own user and group, and an error if the worker is not
+// permitted to switch to them.
+func resolveCredential(userName, groupName string) (*credential, error) {
+	if userName == "" && groupName == "" {
+		return nil, nil
+	}
+
+	uid, gid := uint32(os.Geteuid()), uint32(os.Getegid())
+	var groups []uint32
+	if userName != "" {
+		u, err := lookupUser(userName)
+		if err != nil {
+			return nil, err
+		}
+		uid, gid = parseID(u.Uid), parseID(u.Gid)
+		groupIDs, err := u.GroupIds()
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up groups of user %s: %w", userName, err)
+		}
+		for _, id := range groupIDs {
+			groups = append(groups, parseID(id))
+		}
+	}
+	if groupName != "" {
+		g, err := lookupGroup(groupName)
+		if err != nil {
+			return nil, err
+		}
+		gid = parseID(g.Gid)
+	}
+
+	if uid == uint32(os.Geteuid()) && gid == uint32(os.Getegid()) {
+		return nil, nil
+	}
+	if os.Geteuid() != 0 {
+		return nil, fmt.Errorf("worker runs as uid %d and is not permitted to run jobs as uid %d, gid %d", os.Geteuid(), uid, gid)
+	}
+	return &credential{Uid: uid, Gid: gid, Groups: groups}, nil
+}
+
+// applyCredential makes cmd run with cred.
+func applyCredential(cmd *exec.Cmd, cred *credential) {
+	if cred == nil {
+		return
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Credential = cred
+}
+
+// lookupUser accepts a user name or a numeric uid.
+func lookupUser(name string) (*user.User, error) {
+	u, err := user.Lookup(name)
+	if _, isID := strconv.ParseUint(name, 10, 32); err != nil && isID == nil {
+		u, err = user.LookupId(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unknown user %s: %w", name, err)
+	}
+	return u, nil
+}
+
+// lookupGroup accepts a group name or a numeric gid.
+func lookupGroup(name string) (*user.Group, error) {
+	g, err := user.LookupGroup(name)
+	if _, isID := strconv.ParseUint(name, 10, 32); err != nil && isID == nil {
+		g, err = user.LookupGroupId(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unknown group %s: %w", name, err)
+	}
+	return g, nil
+}
+
+func parseID(id string) uint32 {
+	n, _ := strconv.ParseUint(id, 10, 32)
+	return uint32(n)
+}
@@ -0,0 +1,32 @@
+//go:build !unix
+
+// internal/infra/shell/process_other.go
+package shell
+
+import (
+	"os"
+	"os/exec"
+	"time"
+
+	"distributed-cron/internal/domain"
+)
+
+// configureProcessGroup only bounds how long Wait blocks after cancellation, as process
+// groups are a Unix feature; the command itself is killed by exec.CommandContext.
+func configureProcessGroup(cmd *exec.Cmd, grace time.Duration) (release func()) {
+	cmd.WaitDelay = grace
+	return func() {}
+}
+
+// processResult reports the exit code and CPU time of a finished process.
+func processResult(state *os.ProcessState) *domain.ExecutionResult {
+	if state == nil {
+		return nil
+	}
+	exitCode := state.ExitCode()
+	return &domain.ExecutionResult{
+		ExitCode:  &exitCode,
+		UserCPU:   state.UserTime(),
+		SystemCPU: state.SystemTime(),
+	}
+}
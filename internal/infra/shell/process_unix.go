@@ -0,0 +1,81 @@
+//go:build unix
+
+// internal/infra/shell/process_unix.go
+package shell
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+
+	"distributed-cron/internal/domain"
+
+	"golang.org/x/sys/unix"
+)
+
+// configureProcessGroup makes cmd run in its own process group, so that pipelines and
+// background processes started by the job are stopped together with it. Once the
+// command's context is done, the group receives SIGTERM, and SIGKILL after grace.
+// The returned func must be called after the command has been waited for.
+func configureProcessGroup(cmd *exec.Cmd, grace time.Duration) (release func()) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+
+	var mu sync.Mutex
+	var killTimer *time.Timer
+	cmd.Cancel = func() error {
+		pgid := cmd.Process.Pid
+		err := syscall.Kill(-pgid, syscall.SIGTERM)
+		if errors.Is(err, syscall.ESRCH) {
+			return os.ErrProcessDone
+		}
+
+		mu.Lock()
+		killTimer = time.AfterFunc(grace, func() { syscall.Kill(-pgid, syscall.SIGKILL) })
+		mu.Unlock()
+		return err
+	}
+	// Wait also waits for the output pipes, which a process that left the group may hold
+	// open; give up on them shortly after the group has been killed.
+	cmd.WaitDelay = grace + time.Second
+
+	return func() {
+		mu.Lock()
+		defer mu.Unlock()
+		// Keep the pending SIGKILL only while processes of the group are still alive.
+		if killTimer != nil && syscall.Kill(-cmd.Process.Pid, 0) != nil {
+			killTimer.Stop()
+		}
+	}
+}
+
+// processResult reports the exit code and resource usage of a finished process and the
+// signal that ended it, if any.
+func processResult(state *os.ProcessState) *domain.ExecutionResult {
+	if state == nil {
+		return nil
+	}
+	exitCode := state.ExitCode()
+	result := &domain.ExecutionResult{
+		ExitCode:  &exitCode,
+		UserCPU:   state.UserTime(),
+		SystemCPU: state.SystemTime(),
+	}
+	if status, ok := state.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		result.Signal = unix.SignalName(status.Signal())
+	}
+	if usage, ok := state.SysUsage().(*syscall.Rusage); ok {
+		// ru_maxrss is in kilobytes, except on macOS where it is in bytes.
+		result.MaxRSS = int64(usage.Maxrss) * 1024
+		if runtime.GOOS == "darwin" {
+			result.MaxRSS = int64(usage.Maxrss)
+		}
+	}
+	return result
+}
@@ -2,12 +2,12 @@
 package shell
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"os"
 	"os/exec"
-	"time"
 
 	"distributed-cron/internal/domain"
 	"go.opentelemetry.io/otel"
@@ -18,20 +18,26 @@ import (
 
 // shellTaskExecutor implements domain.TaskExecutor for shell commands.
 type shellTaskExecutor struct {
-	logger *slog.Logger
-	tracer trace.Tracer
+	cgroups *cgroupManager
+	logger  *slog.Logger
+	tracer  trace.Tracer
 }
 
 // NewShellTaskExecutor creates a new shellTaskExecutor instance.
-func NewShellTaskExecutor(logger *slog.Logger) domain.TaskExecutor {
+// Executions with resource limits run in their own cgroup v2 under cgroupParent;
+// if cgroupParent is empty, jobs with resource limits are refused.
+func NewShellTaskExecutor(cgroupParent string, logger *slog.Logger) domain.TaskExecutor {
 	return &shellTaskExecutor{
-		logger: logger.With("executor_type", "shell"),
-		tracer: otel.Tracer("distributed-cron-shell-executor"),
+		cgroups: newCgroupManager(cgroupParent),
+		logger:  logger.With("executor_type", "shell"),
+		tracer:  otel.Tracer("distributed-cron-shell-executor"),
 	}
 }
 
-// Execute runs the shell command specified in the job and returns its output.
-func (e *shellTaskExecutor) Execute(ctx context.Context, job *domain.Job) (string, error) {
+// Execute runs the shell command specified in the job and writes its output to output.
+// Stdout and stderr are written as they are produced, interleaved in the order they arrive.
+// The command runs in its own process group, which is terminated as a whole when ctx is done.
+func (e *shellTaskExecutor) Execute(ctx context.Context, job *domain.Job, output io.Writer) (*domain.ExecutionResult, error) {
 	ctx, span := e.tracer.Start(ctx, "executor.shell.Execute",
 		trace.WithAttributes(
 			attribute.String("job.name", job.Name),
@@ -41,38 +47,100 @@ func (e *shellTaskExecutor) Execute(ctx context.Context, job *domain.Job) (strin
 
 	e.logger.Info("executing shell command", "command", job.Executor.Command, "job_name", job.Name)
 
-	execCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
-
-	cmd := exec.CommandContext(execCtx, "bash", "-c", job.Executor.Command)
+	cred, err := resolveCredential(job.Executor.User, job.Executor.Group)
+	if err != nil {
+		span.SetStatus(codes.Error, "cannot run as requested user")
+		span.RecordError(err)
+		return nil, fmt.Errorf("%w: %w", err, domain.ErrNonRetriable)
+	}
 
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
+	// The execution timeout is applied by the caller through ctx.
+	cmd := exec.CommandContext(ctx, "bash", "-c", job.Executor.Command)
+	cmd.Dir = job.Executor.WorkingDir
+	if len(job.Executor.Env) > 0 {
+		cmd.Env = os.Environ()
+		for k, v := range job.Executor.Env {
+			cmd.Env = append(cmd.Env, k+"="+v)
+		}
+	}
+	applyCredential(cmd, cred)
 
-	err := cmd.Run()
-	output := stdout.String()
-	errOutput := stderr.String()
+	var cg *cgroup
+	if limits := job.Executor.Limits; limits != nil {
+		if cg, err = e.cgroups.create(limits); err != nil {
+			span.SetStatus(codes.Error, "cannot apply resource limits")
+			span.RecordError(err)
+			return nil, fmt.Errorf("%w: %w", err, domain.ErrNonRetriable)
+		}
+		defer cg.destroy()
+		cg.attach(cmd)
+	}
 
-	if output != "" {
-		span.SetAttributes(attribute.String("shell.stdout", output))
+	grace := job.Executor.KillGracePeriod
+	if grace <= 0 {
+		grace = domain.DefaultKillGracePeriod
 	}
-	if errOutput != "" {
-		span.SetAttributes(attribute.String("shell.stderr", errOutput))
-		// Prepend stderr to the main output for visibility
-		if output != "" {
-			output = fmt.Sprintf("[STDERR]:\n%s\n[STDOUT]:\n%s", errOutput, output)
-		} else {
-			output = fmt.Sprintf("[STDERR]:\n%s", errOutput)
-		}
+	release := configureProcessGroup(cmd, grace)
+
+	// The same writer for both streams makes os/exec serialize the writes.
+	counter := &countingWriter{w: output}
+	cmd.Stdout = counter
+	cmd.Stderr = counter
+
+	err = cmd.Run()
+	release()
+	result := processResult(cmd.ProcessState)
+	if result != nil {
+		result.OutputBytes = counter.n
+		result.OOMKilled = cg != nil && cg.oomKilled()
+		span.SetAttributes(
+			attribute.Int("process.exit_code", *result.ExitCode),
+			attribute.String("process.signal", result.Signal),
+			attribute.Int64("process.max_rss_bytes", result.MaxRSS),
+		)
 	}
 
 	if err != nil {
 		span.SetStatus(codes.Error, "shell command failed")
 		span.RecordError(err)
-		return output, fmt.Errorf("shell command failed: %w", err)
+		if result != nil && result.OOMKilled {
+			return result, fmt.Errorf("shell command was killed for exceeding its memory limit: %w", err)
+		}
+		return result, fmt.Errorf("shell command failed: %w", err)
 	}
 
 	e.logger.Info("shell command executed successfully", "job_name", job.Name)
-	return output, nil
+	return result, nil
+}
+
+// Preflight rejects jobs whose working directory, run-as user or resource limits this worker cannot use.
+func (e *shellTaskExecutor) Preflight(job *domain.Job) error {
+	if dir := job.Executor.WorkingDir; dir != "" {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return fmt.Errorf("working directory %s is not available: %w", dir, err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("working directory %s is not a directory", dir)
+		}
+	}
+	if _, err := resolveCredential(job.Executor.User, job.Executor.Group); err != nil {
+		return err
+	}
+	if job.Executor.Limits != nil {
+		return e.cgroups.check(job.Executor.Limits)
+	}
+	return nil
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
 }
@@ -0,0 +1,148 @@
+// internal/infra/sqlite/db.go
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"distributed-cron/internal/domain"
+
+	"github.com/google/uuid"
+	_ "modernc.org/sqlite" // Registers the "sqlite" driver
+)
+
+// ErrOtherDatabase is returned by CheckSameDatabase when the masters use another database.
+var ErrOtherDatabase = errors.New("masters use another SQLite database")
+
+// migrations are applied in order, each once; the schema version is the number of
+// migrations applied. Append new migrations, never change released ones.
+var migrations = []string{
+	`CREATE TABLE jobs (
+		name       TEXT PRIMARY KEY,
+		definition TEXT NOT NULL
+	);
+	CREATE TABLE job_changes (
+		rev     INTEGER PRIMARY KEY AUTOINCREMENT,
+		name    TEXT NOT NULL,
+		deleted INTEGER NOT NULL
+	);
+	CREATE TABLE executions (
+		job_name   TEXT NOT NULL,
+		id         TEXT NOT NULL,
+		status     TEXT NOT NULL,
+		worker_id  TEXT NOT NULL,
+		start_time INTEGER NOT NULL,
+		record     TEXT NOT NULL,
+		PRIMARY KEY (job_name, id)
+	);
+	CREATE INDEX executions_job_start ON executions (job_name, start_time DESC, id DESC);
+	CREATE INDEX executions_status_start ON executions (status, start_time DESC, id DESC);
+	CREATE INDEX executions_worker_start ON executions (worker_id, start_time DESC, id DESC);
+	CREATE INDEX executions_start ON executions (start_time DESC, id DESC);`,
+	// rev counts the saves of a record, so that Save can refuse to overwrite a newer one.
+	`ALTER TABLE executions ADD COLUMN rev INTEGER NOT NULL DEFAULT 1;`,
+	// database_id holds one random ID, set when the database is created, that tells
+	// whether masters and workers opened the same file.
+	`CREATE TABLE database_id (id TEXT NOT NULL);`,
+}
+
+// Open opens the SQLite database at path, creating it if needed, and migrates it to the
+// current schema. WAL mode and a busy timeout let masters and workers on the same host
+// share the database file. SQLite locking is not reliable on network filesystems, so
+// the file must not be shared between hosts; see CheckSameDatabase.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create directory of database %s: %w", path, err)
+	}
+
+	params := url.Values{}
+	params.Add("_pragma", "busy_timeout(5000)")
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "synchronous(NORMAL)")
+	params.Set("_txlock", "immediate") // Take the write lock up front instead of failing on upgrade
+	db, err := sql.Open("sqlite", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database %s: %w", path, err)
+	}
+	if err := migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func migrate(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin schema migration: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_version (version INTEGER NOT NULL)`); err != nil {
+		return fmt.Errorf("failed to create schema version table: %w", err)
+	}
+	var version int
+	err = tx.QueryRowContext(ctx, `SELECT version FROM schema_version`).Scan(&version)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_version (version) VALUES (0)`); err != nil {
+			return fmt.Errorf("failed to initialize schema version: %w", err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("database schema version %d is newer than this binary supports (%d)", version, len(migrations))
+	}
+
+	for i := version; i < len(migrations); i++ {
+		if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+			return fmt.Errorf("failed to apply schema migration %d: %w", i+1, err)
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE schema_version SET version = ?`, len(migrations)); err != nil {
+		return fmt.Errorf("failed to update schema version: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `INSERT INTO database_id (id) SELECT ? WHERE NOT EXISTS (SELECT 1 FROM database_id)`, uuid.NewString()); err != nil {
+		return fmt.Errorf("failed to set database ID: %w", err)
+	}
+	return tx.Commit()
+}
+
+// DatabaseID returns the ID the database was given when it was created.
+func DatabaseID(ctx context.Context, db *sql.DB) (string, error) {
+	var id string
+	if err := db.QueryRowContext(ctx, `SELECT id FROM database_id`).Scan(&id); err != nil {
+		return "", fmt.Errorf("failed to read database ID: %w", err)
+	}
+	return id, nil
+}
+
+// CheckSameDatabase checks that the masters of the cluster use the database with ID
+// databaseID. Masters publish the ID of their database with their candidacy, so a
+// master or worker that opened another file, such as one on another host, fails here
+// instead of writing records no one else reads. It reports whether any master was
+// found to check against.
+func CheckSameDatabase(ctx context.Context, databaseID string, cluster domain.ClusterObserver) (bool, error) {
+	members, err := cluster.Candidates(ctx)
+	if err != nil {
+		return false, fmt.Errorf("failed to list masters: %w", err)
+	}
+	checked := false
+	for _, m := range members {
+		if m.StorageID == "" {
+			continue // A master of an earlier version, or one using etcd storage
+		}
+		if m.StorageID != databaseID {
+			return false, fmt.Errorf("%w: master %s uses database %s, this one is %s; masters and workers must run on one host and open the same file",
+				ErrOtherDatabase, m.NodeID, m.StorageID, databaseID)
+		}
+		checked = true
+	}
+	return checked, nil
+}
@@ -0,0 +1,66 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"distributed-cron/internal/domain"
+)
+
+type fakeCluster []domain.ClusterMember
+
+func (c fakeCluster) Candidates(context.Context) ([]domain.ClusterMember, error) { return c, nil }
+
+func TestDatabaseIDKeptAcrossOpens(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "cron.db")
+	var ids []string
+	for range 2 {
+		db, err := Open(ctx, path)
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		id, err := DatabaseID(ctx, db)
+		db.Close()
+		if err != nil {
+			t.Fatalf("DatabaseID() error = %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if ids[0] == "" || ids[0] != ids[1] {
+		t.Errorf("DatabaseID() = %q, then %q after reopening, want the same ID", ids[0], ids[1])
+	}
+
+	other, err := DatabaseID(ctx, openTestDB(t))
+	if err != nil {
+		t.Fatalf("DatabaseID() error = %v", err)
+	}
+	if other == ids[0] {
+		t.Errorf("DatabaseID() of another database = %q, want a different ID", other)
+	}
+}
+
+func TestCheckSameDatabase(t *testing.T) {
+	tests := []struct {
+		name        string
+		members     fakeCluster
+		wantChecked bool
+		wantErr     error
+	}{
+		{name: "no masters"},
+		{name: "masters without a database", members: fakeCluster{{NodeID: "m1"}}},
+		{name: "same database", members: fakeCluster{{NodeID: "m1", StorageID: "db1"}, {NodeID: "m2"}}, wantChecked: true},
+		{name: "another database", members: fakeCluster{{NodeID: "m1", StorageID: "db1"}, {NodeID: "m2", StorageID: "db2"}}, wantErr: ErrOtherDatabase},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checked, err := CheckSameDatabase(context.Background(), "db1", tt.members)
+			if checked != tt.wantChecked || !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckSameDatabase() = %v, %v, want %v, %v", checked, err, tt.wantChecked, tt.wantErr)
+			}
+		})
+	}
+}
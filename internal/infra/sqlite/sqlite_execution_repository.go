@@ -0,0 +1,375 @@
+// internal/infra/sqlite/sqlite_execution_repository.go
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"distributed-cron/internal/domain"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxDeleteBatch keeps the number of parameters of a DELETE well below SQLite's limit.
+const maxDeleteBatch = 500
+
+type sqliteExecutionRepository struct {
+	db     *sql.DB
+	logger *slog.Logger
+	tracer trace.Tracer
+}
+
+// NewSQLiteExecutionRepository creates a new repository for execution records backed by SQLite.
+func NewSQLiteExecutionRepository(db *sql.DB, logger *slog.Logger) domain.ExecutionRepository {
+	return &sqliteExecutionRepository{
+		db:     db,
+		logger: logger,
+		tracer: otel.Tracer("distributed-cron-sqlite-execution-repo"),
+	}
+}
+
+// position is where a record sorts in the history: newest first, by start time and then by ID.
+// Cursors are the base64 encoded position of the last record of a page.
+type position struct {
+	startTime int64
+	id        string
+}
+
+func (p position) encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(p.startTime, 10) + ":" + p.id))
+}
+
+func decodeCursor(cursor string) (position, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err == nil {
+		if nanos, id, ok := strings.Cut(string(raw), ":"); ok {
+			if startTime, err := strconv.ParseInt(nanos, 10, 64); err == nil && id != "" {
+				return position{startTime: startTime, id: id}, nil
+			}
+		}
+	}
+	return position{}, fmt.Errorf("%w: %q", domain.ErrInvalidCursor, cursor)
+}
+
+// after is the condition that selects the records after p in history order.
+const after = `(start_time < ? OR (start_time = ? AND id < ?))`
+
+func (p position) args() []any {
+	return []any{p.startTime, p.startTime, p.id}
+}
+
+// Save upserts a record. The start time a record was first saved with is kept, so that
+// it does not move in the history.
+func (r *sqliteExecutionRepository) Save(ctx context.Context, record *domain.ExecutionRecord) error {
+	ctx, span := r.tracer.Start(ctx, "repo.sqlite.SaveExecution")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("execution.id", record.ID),
+		attribute.String("job.name", record.JobName),
+	)
+
+	recordJSON, err := json.Marshal(record)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to marshal execution record")
+		return fmt.Errorf("failed to marshal execution record %s to JSON: %w", record.ID, err)
+	}
+
+	// Only overwrite the record at the revision it was read at; a record never saved must not exist yet.
+	var result sql.Result
+	if record.Revision == 0 {
+		result, err = r.db.ExecContext(ctx,
+			`INSERT INTO executions (job_name, id, status, worker_id, start_time, record, rev) VALUES (?, ?, ?, ?, ?, ?, 1)
+			ON CONFLICT (job_name, id) DO NOTHING`,
+			record.JobName, record.ID, string(record.Status), record.WorkerID, record.StartTime.UnixNano(), string(recordJSON))
+	} else {
+		result, err = r.db.ExecContext(ctx,
+			`UPDATE executions SET status = ?, worker_id = ?, record = ?, rev = rev + 1
+			WHERE job_name = ? AND id = ? AND rev = ?`,
+			string(record.Status), record.WorkerID, string(recordJSON), record.JobName, record.ID, record.Revision)
+	}
+	var saved int64
+	if err == nil {
+		saved, err = result.RowsAffected()
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to save execution record to sqlite")
+		return fmt.Errorf("failed to save execution record %s to sqlite: %w", record.ID, err)
+	}
+	if saved == 0 {
+		span.SetStatus(codes.Error, "execution record changed concurrently")
+		return fmt.Errorf("%w: %s/%s", domain.ErrExecutionConflict, record.JobName, record.ID)
+	}
+	record.Revision++
+	return nil
+}
+
+// Get retrieves a single execution record by its JobName and ExecutionID.
+func (r *sqliteExecutionRepository) Get(ctx context.Context, jobName, executionID string) (*domain.ExecutionRecord, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.sqlite.GetExecution")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("job.name", jobName),
+		attribute.String("execution.id", executionID),
+	)
+
+	var (
+		recordJSON string
+		rev        int64
+	)
+	err := r.db.QueryRowContext(ctx, `SELECT record, rev FROM executions WHERE job_name = ? AND id = ?`, jobName, executionID).Scan(&recordJSON, &rev)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("%w: %s/%s", domain.ErrExecutionNotFound, jobName, executionID)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to get execution record from sqlite")
+		return nil, fmt.Errorf("failed to get execution record %s/%s from sqlite: %w", jobName, executionID, err)
+	}
+
+	var record domain.ExecutionRecord
+	if err := json.Unmarshal([]byte(recordJSON), &record); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to unmarshal execution record")
+		return nil, fmt.Errorf("failed to unmarshal execution record %s/%s from JSON: %w", jobName, executionID, err)
+	}
+	record.Revision = rev
+	return &record, nil
+}
+
+// ListByJobName retrieves a page of execution records for a specific job, newest first.
+func (r *sqliteExecutionRepository) ListByJobName(ctx context.Context, jobName, cursor string, pageSize int) ([]*domain.ExecutionRecord, string, error) {
+	return r.Query(ctx, domain.ExecutionQuery{JobName: jobName, Cursor: cursor, Limit: pageSize})
+}
+
+// Query selects the matching records with one indexed statement. One more record than
+// the limit is read to tell whether another page follows.
+func (r *sqliteExecutionRepository) Query(ctx context.Context, q domain.ExecutionQuery) ([]*domain.ExecutionRecord, string, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.sqlite.QueryExecutions")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("job.name", q.JobName),
+		attribute.String("status", string(q.Status)),
+		attribute.String("worker.id", q.WorkerID),
+		attribute.String("cursor", q.Cursor),
+		attribute.Int("limit", q.Limit),
+	)
+
+	var (
+		conds []string
+		args  []any
+	)
+	if q.JobName != "" {
+		conds, args = append(conds, "job_name = ?"), append(args, q.JobName)
+	}
+	if q.Status != "" {
+		conds, args = append(conds, "status = ?"), append(args, string(q.Status))
+	}
+	if q.WorkerID != "" {
+		conds, args = append(conds, "worker_id = ?"), append(args, q.WorkerID)
+	}
+	if !q.Since.IsZero() {
+		conds, args = append(conds, "start_time >= ?"), append(args, q.Since.UnixNano())
+	}
+	if !q.Until.IsZero() {
+		conds, args = append(conds, "start_time <= ?"), append(args, q.Until.UnixNano())
+	}
+	if q.Cursor != "" {
+		pos, err := decodeCursor(q.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		conds, args = append(conds, after), append(args, pos.args()...)
+	}
+
+	records, positions, err := r.selectRecords(ctx, conds, args, q.Limit+1)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to query execution records from sqlite")
+		return nil, "", err
+	}
+
+	var next string
+	if len(records) > q.Limit {
+		records = records[:q.Limit]
+		next = positions[q.Limit-1].encode()
+	}
+	span.SetAttributes(attribute.Int("records_returned", len(records)), attribute.Bool("has_more", next != ""))
+	return records, next, nil
+}
+
+// ListExpired finds the record after the newest keep ones and returns it and the records
+// after it, together with the records that started at or before before.
+func (r *sqliteExecutionRepository) ListExpired(ctx context.Context, jobName string, keep int, before time.Time, cursor string, limit int) ([]*domain.ExecutionRecord, string, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.sqlite.ListExpiredExecutions")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("job.name", jobName),
+		attribute.Int("keep", keep),
+		attribute.String("before", before.Format(time.RFC3339)),
+		attribute.String("cursor", cursor),
+	)
+
+	var (
+		expired []string
+		args    []any
+	)
+	if !before.IsZero() {
+		expired, args = append(expired, "start_time <= ?"), append(args, before.UnixNano())
+	}
+	if keep > 0 {
+		var last position
+		err := r.db.QueryRowContext(ctx,
+			`SELECT start_time, id FROM executions WHERE job_name = ?
+			ORDER BY start_time DESC, id DESC LIMIT 1 OFFSET ?`, jobName, keep-1).Scan(&last.startTime, &last.id)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			// Fewer than keep records.
+		case err != nil:
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to find the oldest kept execution record")
+			return nil, "", fmt.Errorf("failed to list expired execution records for job %s from sqlite: %w", jobName, err)
+		default:
+			expired, args = append(expired, after), append(args, last.args()...)
+		}
+	}
+	if len(expired) == 0 {
+		return nil, "", nil
+	}
+
+	conds := []string{"job_name = ?", "(" + strings.Join(expired, " OR ") + ")"}
+	args = append([]any{jobName}, args...)
+	if cursor != "" {
+		pos, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		conds, args = append(conds, after), append(args, pos.args()...)
+	}
+	records, positions, err := r.selectRecords(ctx, conds, args, limit+1)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to list expired execution records from sqlite")
+		return nil, "", err
+	}
+
+	var next string
+	if len(records) > limit {
+		records = records[:limit]
+		next = positions[limit-1].encode()
+	}
+	span.SetAttributes(attribute.Int("records_returned", len(records)), attribute.Bool("has_more", next != ""))
+	return records, next, nil
+}
+
+// Delete removes execution records of a job, maxDeleteBatch at a time.
+func (r *sqliteExecutionRepository) Delete(ctx context.Context, jobName string, executionIDs []string) (int, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.sqlite.DeleteExecutions")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", jobName), attribute.Int("records", len(executionIDs)))
+
+	deleted := 0
+	for len(executionIDs) > 0 {
+		chunk := executionIDs[:min(maxDeleteBatch, len(executionIDs))]
+		executionIDs = executionIDs[len(chunk):]
+
+		args := make([]any, 0, len(chunk)+1)
+		args = append(args, jobName)
+		for _, id := range chunk {
+			args = append(args, id)
+		}
+		placeholders := strings.TrimSuffix(strings.Repeat("?,", len(chunk)), ",")
+		result, err := r.db.ExecContext(ctx, `DELETE FROM executions WHERE job_name = ? AND id IN (`+placeholders+`)`, args...)
+		var n int64
+		if err == nil {
+			n, err = result.RowsAffected()
+		}
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "failed to delete execution records from sqlite")
+			return deleted, fmt.Errorf("failed to delete execution records of job %s from sqlite: %w", jobName, err)
+		}
+		deleted += int(n)
+	}
+	return deleted, nil
+}
+
+// ListJobNames returns the names of all jobs that have execution records.
+func (r *sqliteExecutionRepository) ListJobNames(ctx context.Context) ([]string, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.sqlite.ListExecutionJobNames")
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT DISTINCT job_name FROM executions`)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to list execution history from sqlite")
+		return nil, fmt.Errorf("failed to list jobs with execution records from sqlite: %w", err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to list jobs with execution records from sqlite: %w", err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list jobs with execution records from sqlite: %w", err)
+	}
+	span.SetAttributes(attribute.Int("jobs_returned", len(names)))
+	return names, nil
+}
+
+// selectRecords returns up to limit records that match all conds, newest first, and
+// their positions in the history.
+func (r *sqliteExecutionRepository) selectRecords(ctx context.Context, conds []string, args []any, limit int) ([]*domain.ExecutionRecord, []position, error) {
+	query := `SELECT start_time, id, record, rev FROM executions`
+	if len(conds) > 0 {
+		query += ` WHERE ` + strings.Join(conds, " AND ")
+	}
+	query += ` ORDER BY start_time DESC, id DESC LIMIT ?`
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit)...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to select execution records from sqlite: %w", err)
+	}
+	defer rows.Close()
+
+	var (
+		records   []*domain.ExecutionRecord
+		positions []position
+	)
+	for rows.Next() {
+		var (
+			pos        position
+			recordJSON string
+			rev        int64
+		)
+		if err := rows.Scan(&pos.startTime, &pos.id, &recordJSON, &rev); err != nil {
+			return nil, nil, fmt.Errorf("failed to select execution records from sqlite: %w", err)
+		}
+		var record domain.ExecutionRecord
+		if err := json.Unmarshal([]byte(recordJSON), &record); err != nil {
+			r.logger.Warn("failed to unmarshal execution record from sqlite", "execution_id", pos.id, "error", err)
+			continue
+		}
+		record.Revision = rev
+		records = append(records, &record)
+		positions = append(positions, pos)
+	}
+	return records, positions, rows.Err()
+}
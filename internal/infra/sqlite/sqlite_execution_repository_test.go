@@ -0,0 +1,290 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"distributed-cron/internal/domain"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := Open(context.Background(), filepath.Join(t.TempDir(), "cron.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newTestExecutionRepository(t *testing.T) domain.ExecutionRepository {
+	t.Helper()
+	return NewSQLiteExecutionRepository(openTestDB(t), slog.New(slog.DiscardHandler))
+}
+
+// saveRecords saves one record per status in statuses, the first one newest, a minute apart.
+func saveRecords(t *testing.T, repo domain.ExecutionRepository, jobName, workerID string, statuses ...domain.ExecutionStatus) {
+	t.Helper()
+	newest := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	for i, status := range statuses {
+		record := &domain.ExecutionRecord{
+			ID:        fmt.Sprintf("%s-%02d", jobName, i),
+			JobName:   jobName,
+			Status:    status,
+			WorkerID:  workerID,
+			StartTime: newest.Add(-time.Duration(i) * time.Minute),
+		}
+		if err := repo.Save(context.Background(), record); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+}
+
+func recordIDs(records []*domain.ExecutionRecord) []string {
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i] = record.ID
+	}
+	return ids
+}
+
+func TestSaveKeepsStartTimeAndRejectsStaleRevision(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestExecutionRepository(t)
+	start := time.Date(2024, 3, 10, 9, 0, 0, 0, time.UTC)
+	record := &domain.ExecutionRecord{ID: "e1", JobName: "report", Status: domain.ExecutionStatusRunning, StartTime: start}
+	if err := repo.Save(ctx, record); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := repo.Save(ctx, &domain.ExecutionRecord{ID: "e1", JobName: "report", StartTime: start}); !errors.Is(err, domain.ErrExecutionConflict) {
+		t.Fatalf("Save() of a second new record with the same ID error = %v, want ErrExecutionConflict", err)
+	}
+
+	stale, err := repo.Get(ctx, "report", "e1")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	current := *stale
+	current.Status = domain.ExecutionStatusSuccess
+	current.StartTime = start.Add(-time.Hour) // A later save must not move the record
+	if err := repo.Save(ctx, &current); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := repo.Save(ctx, &current); err != nil {
+		t.Fatalf("Save() at the revision the previous save returned error = %v", err)
+	}
+
+	stale.Status = domain.ExecutionStatusFailed
+	if err := repo.Save(ctx, stale); !errors.Is(err, domain.ErrExecutionConflict) {
+		t.Fatalf("Save() of a stale record error = %v, want ErrExecutionConflict", err)
+	}
+
+	saveRecords(t, repo, "report", "w1", domain.ExecutionStatusSuccess) // report-00 starts at start
+	records, _, err := repo.ListByJobName(ctx, "report", "", 10)
+	if err != nil {
+		t.Fatalf("ListByJobName() error = %v", err)
+	}
+	if got := recordIDs(records); !slices.Equal(got, []string{"report-00", "e1"}) || records[1].Status != domain.ExecutionStatusSuccess {
+		t.Fatalf("ListByJobName() = %v, want [report-00 e1] with e1 as saved last", got)
+	}
+}
+
+func TestGetMissingExecution(t *testing.T) {
+	repo := newTestExecutionRepository(t)
+	saveRecords(t, repo, "report", "w1", domain.ExecutionStatusSuccess)
+
+	tests := []struct {
+		jobName, executionID string
+	}{
+		{jobName: "report", executionID: "report-01"},
+		{jobName: "other", executionID: "report-00"}, // Records are found under their own job only
+	}
+	for _, tt := range tests {
+		if _, err := repo.Get(context.Background(), tt.jobName, tt.executionID); !errors.Is(err, domain.ErrExecutionNotFound) {
+			t.Errorf("Get(%q, %q) error = %v, want ErrExecutionNotFound", tt.jobName, tt.executionID, err)
+		}
+	}
+}
+
+func TestListByJobNamePages(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestExecutionRepository(t)
+	saveRecords(t, repo, "report", "w1", slices.Repeat([]domain.ExecutionStatus{domain.ExecutionStatusSuccess}, 5)...)
+	saveRecords(t, repo, "report-2", "w1", domain.ExecutionStatusSuccess)
+
+	var pages [][]string
+	cursor := ""
+	for {
+		records, next, err := repo.ListByJobName(ctx, "report", cursor, 2)
+		if err != nil {
+			t.Fatalf("ListByJobName(%q) error = %v", cursor, err)
+		}
+		pages = append(pages, recordIDs(records))
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	want := [][]string{{"report-00", "report-01"}, {"report-02", "report-03"}, {"report-04"}}
+	if !slices.EqualFunc(pages, want, slices.Equal[[]string]) {
+		t.Errorf("pages = %v, want %v", pages, want)
+	}
+}
+
+func TestListByJobNameInvalidCursor(t *testing.T) {
+	repo := newTestExecutionRepository(t)
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not base64", cursor: "not base64!"},
+		{name: "no separator", cursor: "MQ"},              // "1"
+		{name: "start time not a number", cursor: "eDpl"}, // "x:e"
+		{name: "no ID", cursor: "MTo"},                    // "1:"
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := repo.ListByJobName(context.Background(), "report", tt.cursor, 10); !errors.Is(err, domain.ErrInvalidCursor) {
+				t.Errorf("ListByJobName(%q) error = %v, want ErrInvalidCursor", tt.cursor, err)
+			}
+		})
+	}
+}
+
+func TestQueryAcrossJobs(t *testing.T) {
+	repo := newTestExecutionRepository(t)
+	saveRecords(t, repo, "a", "w1", domain.ExecutionStatusFailed, domain.ExecutionStatusSuccess)
+	saveRecords(t, repo, "b", "w2", domain.ExecutionStatusSuccess, domain.ExecutionStatusFailed)
+
+	tests := []struct {
+		name  string
+		query domain.ExecutionQuery
+		want  []string
+	}{
+		{name: "all, newest first", query: domain.ExecutionQuery{Limit: 10}, want: []string{"b-00", "a-00", "b-01", "a-01"}},
+		{name: "by status", query: domain.ExecutionQuery{Status: domain.ExecutionStatusFailed, Limit: 10}, want: []string{"a-00", "b-01"}},
+		{name: "by worker", query: domain.ExecutionQuery{WorkerID: "w2", Limit: 10}, want: []string{"b-00", "b-01"}},
+		{name: "by worker and status", query: domain.ExecutionQuery{WorkerID: "w2", Status: domain.ExecutionStatusFailed, Limit: 10}, want: []string{"b-01"}},
+		{name: "by job and status", query: domain.ExecutionQuery{JobName: "a", Status: domain.ExecutionStatusSuccess, Limit: 10}, want: []string{"a-01"}},
+		{
+			name:  "by time",
+			query: domain.ExecutionQuery{Since: time.Date(2024, 3, 10, 8, 59, 0, 0, time.UTC), Until: time.Date(2024, 3, 10, 8, 59, 0, 0, time.UTC), Limit: 10},
+			want:  []string{"b-01", "a-01"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records, next, err := repo.Query(context.Background(), tt.query)
+			if err != nil {
+				t.Fatalf("Query() error = %v", err)
+			}
+			if got := recordIDs(records); !slices.Equal(got, tt.want) || next != "" {
+				t.Errorf("Query() = %v, %q, want %v, \"\"", got, next, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueryPages(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestExecutionRepository(t)
+	saveRecords(t, repo, "report", "w1", domain.ExecutionStatusFailed, domain.ExecutionStatusSuccess, domain.ExecutionStatusFailed, domain.ExecutionStatusFailed)
+
+	q := domain.ExecutionQuery{Status: domain.ExecutionStatusFailed, Limit: 2}
+	records, next, err := repo.Query(ctx, q)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if got := recordIDs(records); !slices.Equal(got, []string{"report-00", "report-02"}) || next == "" {
+		t.Fatalf("Query() = %v, %q, want [report-00 report-02] and a cursor", got, next)
+	}
+
+	q.Cursor = next
+	records, next, err = repo.Query(ctx, q)
+	if err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if got := recordIDs(records); !slices.Equal(got, []string{"report-03"}) || next != "" {
+		t.Errorf("Query() = %v, %q, want [report-03], \"\"", got, next)
+	}
+
+	q.Cursor = "not base64!"
+	if _, _, err := repo.Query(ctx, q); !errors.Is(err, domain.ErrInvalidCursor) {
+		t.Errorf("Query() with an invalid cursor error = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestListExpired(t *testing.T) {
+	repo := newTestExecutionRepository(t)
+	// report-00 started at 09:00, report-05 at 08:55.
+	saveRecords(t, repo, "report", "w1", slices.Repeat([]domain.ExecutionStatus{domain.ExecutionStatusSuccess}, 6)...)
+	saveRecords(t, repo, "other", "w1", domain.ExecutionStatusSuccess)
+
+	tests := []struct {
+		name   string
+		keep   int
+		before time.Time
+		want   []string
+	}{
+		{name: "nothing to expire", want: nil},
+		{name: "beyond keep", keep: 4, want: []string{"report-04", "report-05"}},
+		{name: "keep more than stored", keep: 10, want: nil},
+		{name: "started at or before before", before: time.Date(2024, 3, 10, 8, 57, 0, 0, time.UTC), want: []string{"report-03", "report-04", "report-05"}},
+		{name: "either", keep: 5, before: time.Date(2024, 3, 10, 8, 56, 0, 0, time.UTC), want: []string{"report-04", "report-05"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			cursor := ""
+			for {
+				// Pages of one record exercise the cursor.
+				records, next, err := repo.ListExpired(context.Background(), "report", tt.keep, tt.before, cursor, 1)
+				if err != nil {
+					t.Fatalf("ListExpired() error = %v", err)
+				}
+				got = append(got, recordIDs(records)...)
+				if next == "" {
+					break
+				}
+				cursor = next
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("ListExpired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteAndListJobNames(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestExecutionRepository(t)
+	saveRecords(t, repo, "report", "w1", slices.Repeat([]domain.ExecutionStatus{domain.ExecutionStatusSuccess}, maxDeleteBatch+2)...)
+	saveRecords(t, repo, "other", "w1", domain.ExecutionStatusSuccess)
+
+	ids := []string{"report-00", "missing", "other-00"} // other-00 is not a record of report
+	for i := 1; i <= maxDeleteBatch+1; i++ {
+		ids = append(ids, fmt.Sprintf("report-%02d", i))
+	}
+	deleted, err := repo.Delete(ctx, "report", ids)
+	if err != nil || deleted != maxDeleteBatch+2 {
+		t.Fatalf("Delete() = %d, %v, want %d, nil", deleted, err, maxDeleteBatch+2)
+	}
+
+	names, err := repo.ListJobNames(ctx)
+	if err != nil {
+		t.Fatalf("ListJobNames() error = %v", err)
+	}
+	if !slices.Equal(names, []string{"other"}) {
+		t.Errorf("ListJobNames() = %v, want [other]", names)
+	}
+}
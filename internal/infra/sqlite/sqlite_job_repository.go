@@ -0,0 +1,300 @@
+// internal/infra/sqlite/sqlite_job_repository.go
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"distributed-cron/internal/domain"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// jobChangesKept is how many entries of the job change log are kept. Watchers poll far
+// more often than this many changes are made, so older entries are no longer needed.
+const jobChangesKept = 1000
+
+// defaultJobPollInterval is used when the watcher is created without a poll interval.
+const defaultJobPollInterval = time.Second
+
+type sqliteJobRepository struct {
+	db           *sql.DB
+	pollInterval time.Duration
+	logger       *slog.Logger
+	tracer       trace.Tracer
+}
+
+// NewSQLiteJobRepository creates a new repository for jobs backed by SQLite.
+func NewSQLiteJobRepository(db *sql.DB, logger *slog.Logger) domain.JobRepository {
+	return &sqliteJobRepository{
+		db:     db,
+		logger: logger,
+		tracer: otel.Tracer("distributed-cron-sqlite-repo"),
+	}
+}
+
+// NewSQLiteJobWatcher creates a watcher that polls the job change log every pollInterval.
+func NewSQLiteJobWatcher(db *sql.DB, pollInterval time.Duration, logger *slog.Logger) domain.JobWatcher {
+	if pollInterval <= 0 {
+		pollInterval = defaultJobPollInterval
+	}
+	return &sqliteJobRepository{
+		db:           db,
+		pollInterval: pollInterval,
+		logger:       logger.With("component", "job-watcher"),
+		tracer:       otel.Tracer("distributed-cron-sqlite-repo"),
+	}
+}
+
+// Save upserts the job and appends the change to the job change log in one transaction.
+func (r *sqliteJobRepository) Save(ctx context.Context, job *domain.Job) error {
+	ctx, span := r.tracer.Start(ctx, "repo.sqlite.Save")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", job.Name))
+
+	jobJSON, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job to JSON: %w", err)
+	}
+
+	err = r.logChange(ctx, job.Name, false,
+		`INSERT INTO jobs (name, definition) VALUES (?, ?)
+		ON CONFLICT (name) DO UPDATE SET definition = excluded.definition`,
+		job.Name, string(jobJSON))
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to save job to sqlite")
+		return fmt.Errorf("failed to save job %s to sqlite: %w", job.Name, err)
+	}
+	return nil
+}
+
+// Delete removes a job and appends the change to the job change log in one transaction.
+func (r *sqliteJobRepository) Delete(ctx context.Context, name string) error {
+	ctx, span := r.tracer.Start(ctx, "repo.sqlite.Delete")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", name))
+
+	if err := r.logChange(ctx, name, true, `DELETE FROM jobs WHERE name = ?`, name); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to delete job from sqlite")
+		return fmt.Errorf("failed to delete job %s from sqlite: %w", name, err)
+	}
+	return nil
+}
+
+// logChange runs the statement and records the change in the same transaction, and
+// trims the change log.
+func (r *sqliteJobRepository) logChange(ctx context.Context, name string, deleted bool, query string, args ...any) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, `INSERT INTO job_changes (name, deleted) VALUES (?, ?)`, name, deleted)
+	if err != nil {
+		return err
+	}
+	rev, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM job_changes WHERE rev <= ?`, rev-jobChangesKept); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Get retrieves a job from SQLite.
+func (r *sqliteJobRepository) Get(ctx context.Context, name string) (*domain.Job, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.sqlite.Get")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", name))
+
+	job, err := r.get(ctx, name)
+	if err != nil && !errors.Is(err, domain.ErrJobNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to get job from sqlite")
+	}
+	return job, err
+}
+
+func (r *sqliteJobRepository) get(ctx context.Context, name string) (*domain.Job, error) {
+	var definition string
+	err := r.db.QueryRowContext(ctx, `SELECT definition FROM jobs WHERE name = ?`, name).Scan(&definition)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrJobNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job %s from sqlite: %w", name, err)
+	}
+
+	var job domain.Job
+	if err := json.Unmarshal([]byte(definition), &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job %s from JSON: %w", name, err)
+	}
+	return &job, nil
+}
+
+// List retrieves all jobs from SQLite, ordered by name.
+func (r *sqliteJobRepository) List(ctx context.Context) ([]*domain.Job, error) {
+	ctx, span := r.tracer.Start(ctx, "repo.sqlite.List")
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, `SELECT name, definition FROM jobs ORDER BY name`)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to list jobs from sqlite")
+		return nil, fmt.Errorf("failed to list jobs from sqlite: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*domain.Job
+	for rows.Next() {
+		var name, definition string
+		if err := rows.Scan(&name, &definition); err != nil {
+			return nil, fmt.Errorf("failed to list jobs from sqlite: %w", err)
+		}
+		var job domain.Job
+		if err := json.Unmarshal([]byte(definition), &job); err != nil {
+			r.logger.Warn("failed to unmarshal job from sqlite", "job_name", name, "error", err)
+			continue
+		}
+		jobs = append(jobs, &job)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list jobs from sqlite: %w", err)
+	}
+	span.SetAttributes(attribute.Int("jobs_returned", len(jobs)))
+	return jobs, nil
+}
+
+// Watch emits the stored jobs and then polls the job change log for entries after the
+// latest one at the time of that list. A put whose job has been deleted since is skipped;
+// the delete follows in the log. If the watcher falls so far behind that the entries it
+// needs have been trimmed, it lists the jobs again.
+func (r *sqliteJobRepository) Watch(ctx context.Context) <-chan domain.JobEvent {
+	events := make(chan domain.JobEvent)
+
+	go func() {
+		defer close(events)
+		ticker := time.NewTicker(r.pollInterval)
+		defer ticker.Stop()
+
+		lastRev, synced := r.syncJobs(ctx, events)
+		for {
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+
+			if !synced {
+				lastRev, synced = r.syncJobs(ctx, events)
+				continue
+			}
+			changes, err := r.changesAfter(ctx, lastRev)
+			if errors.Is(err, errChangesTrimmed) {
+				r.logger.Warn("job change log was trimmed past the watcher, re-listing jobs", "rev", lastRev)
+				synced = false
+				continue
+			}
+			if err != nil {
+				r.logger.Error("failed to poll job change log", "error", err)
+				continue
+			}
+			for _, change := range changes {
+				lastRev = change.rev
+				event := domain.JobEvent{Type: domain.JobEventDelete, Name: change.name}
+				if !change.deleted {
+					job, err := r.get(ctx, change.name)
+					if errors.Is(err, domain.ErrJobNotFound) {
+						continue
+					}
+					if err != nil {
+						r.logger.Warn("failed to load changed job", "job_name", change.name, "error", err)
+						continue
+					}
+					event.Type = domain.JobEventPut
+					event.Job = job
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return events
+}
+
+// syncJobs emits a JobEventSync with every stored job and returns the latest entry of
+// the change log before the list. Changes made during the list are replayed afterwards.
+func (r *sqliteJobRepository) syncJobs(ctx context.Context, events chan<- domain.JobEvent) (int64, bool) {
+	var lastRev int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(rev), 0) FROM job_changes`).Scan(&lastRev); err != nil {
+		r.logger.Error("failed to read job change log", "error", err)
+		return 0, false
+	}
+	jobs, err := r.List(ctx)
+	if err != nil {
+		r.logger.Error("failed to list jobs for the job watch", "error", err)
+		return 0, false
+	}
+
+	select {
+	case events <- domain.JobEvent{Type: domain.JobEventSync, Jobs: jobs}:
+		return lastRev, true
+	case <-ctx.Done():
+		return 0, false
+	}
+}
+
+// errChangesTrimmed is returned by changesAfter when entries after the requested
+// revision are no longer in the change log.
+var errChangesTrimmed = errors.New("job change log trimmed")
+
+type jobChange struct {
+	rev     int64
+	name    string
+	deleted bool
+}
+
+func (r *sqliteJobRepository) changesAfter(ctx context.Context, rev int64) ([]jobChange, error) {
+	rows, err := r.db.QueryContext(ctx, `SELECT rev, name, deleted FROM job_changes WHERE rev > ? ORDER BY rev`, rev)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []jobChange
+	for rows.Next() {
+		var change jobChange
+		if err := rows.Scan(&change.rev, &change.name, &change.deleted); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// Revisions are consecutive, so a gap after rev means its successors were trimmed.
+	if len(changes) > 0 && changes[0].rev > rev+1 {
+		return nil, errChangesTrimmed
+	}
+	return changes, nil
+}
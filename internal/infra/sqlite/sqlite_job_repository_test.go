@@ -0,0 +1,140 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"distributed-cron/internal/domain"
+)
+
+// nextEvent returns the next event of the watch, failing the test if none arrives in time.
+func nextEvent(t *testing.T, events <-chan domain.JobEvent) domain.JobEvent {
+	t.Helper()
+	select {
+	case event, ok := <-events:
+		if !ok {
+			t.Fatalf("job watch closed")
+		}
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no job event")
+	}
+	return domain.JobEvent{}
+}
+
+func jobNames(jobs []*domain.Job) []string {
+	names := make([]string, len(jobs))
+	for i, job := range jobs {
+		names[i] = job.Name
+	}
+	return names
+}
+
+func TestChangesAfter(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := NewSQLiteJobRepository(db, slog.New(slog.DiscardHandler)).(*sqliteJobRepository)
+	for _, name := range []string{"a", "b"} {
+		if err := repo.Save(ctx, &domain.Job{Name: name}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	if err := repo.Delete(ctx, "a"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		trimmed int64 // Entries up to this revision are removed from the log first
+		rev     int64
+		want    []jobChange
+		wantErr error
+	}{
+		{name: "all", rev: 0, want: []jobChange{{1, "a", false}, {2, "b", false}, {3, "a", true}}},
+		{name: "after a revision", rev: 2, want: []jobChange{{3, "a", true}}},
+		{name: "up to date", rev: 3, want: nil},
+		{name: "after trimmed entries", trimmed: 1, rev: 1, want: []jobChange{{2, "b", false}, {3, "a", true}}},
+		{name: "entries needed were trimmed", trimmed: 2, rev: 1, wantErr: errChangesTrimmed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := db.Exec(`DELETE FROM job_changes WHERE rev <= ?`, tt.trimmed); err != nil {
+				t.Fatalf("failed to trim job change log: %v", err)
+			}
+			got, err := repo.changesAfter(ctx, tt.rev)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("changesAfter(%d) error = %v, want %v", tt.rev, err, tt.wantErr)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("changesAfter(%d) = %v, want %v", tt.rev, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	db := openTestDB(t)
+	repo := NewSQLiteJobRepository(db, slog.New(slog.DiscardHandler))
+	for _, name := range []string{"b", "a"} {
+		if err := repo.Save(ctx, &domain.Job{Name: name}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	events := NewSQLiteJobWatcher(db, 10*time.Millisecond, slog.New(slog.DiscardHandler)).Watch(ctx)
+	event := nextEvent(t, events)
+	if event.Type != domain.JobEventSync || !slices.Equal(jobNames(event.Jobs), []string{"a", "b"}) {
+		t.Fatalf("first event = %s %v, want sync [a b]", event.Type, jobNames(event.Jobs))
+	}
+
+	if err := repo.Save(ctx, &domain.Job{Name: "c", CronExpr: "@hourly"}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	event = nextEvent(t, events)
+	if event.Type != domain.JobEventPut || event.Name != "c" || event.Job == nil || event.Job.CronExpr != "@hourly" {
+		t.Fatalf("event = %+v, want put of c", event)
+	}
+	if err := repo.Delete(ctx, "a"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	event = nextEvent(t, events)
+	if event.Type != domain.JobEventDelete || event.Name != "a" || event.Job != nil {
+		t.Fatalf("event = %+v, want delete of a", event)
+	}
+
+	// Entries the watcher has not read yet are trimmed from the log: it lists the jobs again.
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	_, err = tx.Exec(`INSERT INTO jobs (name, definition) VALUES ('d', '{"name":"d"}')`)
+	if err == nil {
+		_, err = tx.Exec(`INSERT INTO job_changes (rev, name, deleted) VALUES ((SELECT MAX(rev) FROM job_changes) + 5, 'd', 0)`)
+	}
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil {
+		t.Fatalf("failed to write job change: %v", err)
+	}
+	event = nextEvent(t, events)
+	if event.Type != domain.JobEventSync || !slices.Equal(jobNames(event.Jobs), []string{"b", "c", "d"}) {
+		t.Fatalf("event = %s %v, want sync [b c d]", event.Type, jobNames(event.Jobs))
+	}
+
+	// Watching continues from the log after the re-list.
+	if err := repo.Delete(ctx, "b"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	event = nextEvent(t, events)
+	if event.Type != domain.JobEventDelete || event.Name != "b" {
+		t.Fatalf("event = %+v, want delete of b", event)
+	}
+}
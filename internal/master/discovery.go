@@ -3,10 +3,15 @@ package master
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"log/slog"
+	"strings"
 	"sync"
 	"time"
 
+	"distributed-cron/internal/domain"
+
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
 
@@ -19,7 +24,7 @@ const (
 type WorkerDiscovery struct {
 	client  *clientv3.Client
 	logger  *slog.Logger
-	workers map[string]string // map of workerID -> workerAddr
+	workers map[string]WorkerInfo // map of registry key -> worker
 	mu      sync.RWMutex
 }
 
@@ -28,7 +33,7 @@ func NewWorkerDiscovery(client *clientv3.Client, logger *slog.Logger) *WorkerDis
 	return &WorkerDiscovery{
 		client:  client,
 		logger:  logger.With("component", "worker-discovery"),
-		workers: make(map[string]string),
+		workers: make(map[string]WorkerInfo),
 	}
 }
 
@@ -48,19 +53,19 @@ func (d *WorkerDiscovery) WatchWorkers(ctx context.Context) {
 	for watchResp := range watchChan {
 		for _, event := range watchResp.Events {
 			workerID := string(event.Kv.Key)
-			workerAddr := string(event.Kv.Value)
 
 			d.mu.Lock()
 			switch event.Type {
 			case clientv3.EventTypePut:
-				// A new worker registered or an existing one's lease was updated
+				// A new worker registered or an existing one published its load
+				worker := parseWorker(workerID, event.Kv.Value, event.Kv.Lease)
 				if _, ok := d.workers[workerID]; !ok {
-					d.logger.Info("new worker discovered", "id", workerID, "addr", workerAddr)
+					d.logger.Info("new worker discovered", "id", workerID, "addr", worker.Addr)
 				}
-				d.workers[workerID] = workerAddr
+				d.workers[workerID] = worker
 			case clientv3.EventTypeDelete:
 				// A worker deregistered (lease expired or graceful shutdown)
-				d.logger.Info("worker deregistered", "id", workerID, "addr", d.workers[workerID])
+				d.logger.Info("worker deregistered", "id", workerID, "addr", d.workers[workerID].Addr)
 				delete(d.workers, workerID)
 			}
 			d.mu.Unlock()
@@ -82,22 +87,97 @@ func (d *WorkerDiscovery) loadInitialWorkers(ctx context.Context) error {
 	defer d.mu.Unlock()
 	for _, kv := range resp.Kvs {
 		workerID := string(kv.Key)
-		workerAddr := string(kv.Value)
-		d.logger.Info("found existing worker", "id", workerID, "addr", workerAddr)
-		d.workers[workerID] = workerAddr
+		worker := parseWorker(workerID, kv.Value, kv.Lease)
+		d.logger.Info("found existing worker", "id", workerID, "addr", worker.Addr)
+		d.workers[workerID] = worker
 	}
 	return nil
 }
 
+// WorkerInfo describes a registered worker.
+type WorkerInfo struct {
+	ID       string
+	Addr     string
+	Running  int // Executions in progress, as last published by the worker plus those dispatched since
+	Capacity int // 0 if the worker did not publish one
+	Labels   map[string]string
+
+	RegisteredAt time.Time        // Zero if the worker did not publish it
+	LeaseID      clientv3.LeaseID // Lease that keeps the registration alive
+}
+
+// Load returns how busy the worker is: the share of its capacity in use, or the number
+// of running executions if it has no capacity.
+func (w WorkerInfo) Load() float64 {
+	if w.Capacity > 0 {
+		return float64(w.Running) / float64(w.Capacity)
+	}
+	return float64(w.Running)
+}
+
+// parseWorker reads a registration value. Workers that predate load reporting register
+// their bare address.
+func parseWorker(key string, value []byte, lease int64) WorkerInfo {
+	worker := WorkerInfo{ID: strings.TrimPrefix(key, WorkerRegistryPrefix), LeaseID: clientv3.LeaseID(lease)}
+	var registration domain.WorkerRegistration
+	if err := json.Unmarshal(value, &registration); err != nil {
+		worker.Addr = string(value)
+		return worker
+	}
+	worker.Addr = registration.Addr
+	worker.Running = registration.Running
+	worker.Capacity = registration.Capacity
+	worker.Labels = registration.Labels
+	worker.RegisteredAt = registration.RegisteredAt
+	return worker
+}
 
-// GetWorkers returns a snapshot of the current available worker addresses.
-func (d *WorkerDiscovery) GetWorkers() []string {
+// GetWorkers returns a snapshot of the currently available workers.
+func (d *WorkerDiscovery) GetWorkers() []WorkerInfo {
 	d.mu.RLock()
 	defer d.mu.RUnlock()
-	
-	addrs := make([]string, 0, len(d.workers))
-	for _, addr := range d.workers {
-		addrs = append(addrs, addr)
+
+	workers := make([]WorkerInfo, 0, len(d.workers))
+	for _, worker := range d.workers {
+		workers = append(workers, worker)
 	}
-	return addrs
+	return workers
+}
+
+// leaseInfo returns how many seconds the registration lease of worker has left and when
+// the worker last renewed it. Workers renew their lease as their heartbeat.
+func (d *WorkerDiscovery) leaseInfo(ctx context.Context, worker WorkerInfo) (int64, time.Time, error) {
+	if worker.LeaseID == clientv3.NoLease {
+		return 0, time.Time{}, nil
+	}
+	resp, err := d.client.TimeToLive(ctx, worker.LeaseID)
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to get lease of worker %s: %w", worker.ID, err)
+	}
+	if resp.TTL <= 0 {
+		return 0, time.Time{}, nil // Expired; the worker is about to be removed
+	}
+	renewedAt := time.Now().Add(-time.Duration(resp.GrantedTTL-resp.TTL) * time.Second)
+	return resp.TTL, renewedAt, nil
+}
+
+// noteDispatched counts an execution dispatched to a worker towards its load until the
+// worker publishes its next count, so that a burst of dispatches is spread out.
+func (d *WorkerDiscovery) noteDispatched(workerID string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if worker, ok := d.workers[WorkerRegistryPrefix+workerID]; ok {
+		worker.Running++
+		d.workers[WorkerRegistryPrefix+workerID] = worker
+	}
+}
+
+// GetWorkerAddr returns the address of the worker registered under workerID.
+func (d *WorkerDiscovery) GetWorkerAddr(workerID string) (string, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	worker, ok := d.workers[WorkerRegistryPrefix+workerID]
+	return worker.Addr, ok
 }
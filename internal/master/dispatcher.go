@@ -3,80 +3,375 @@ package master
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
-	"math/rand"
+	"slices"
 	"sync"
+	"time"
 
 	"distributed-cron/internal/domain"
+	"distributed-cron/internal/metrics"
 	pb "distributed-cron/proto"
 
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"google.golang.org/grpc"
+	grpccodes "google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // Dispatcher handles dispatching tasks to available workers.
 type Dispatcher struct {
 	discovery *WorkerDiscovery
-	clients   map[string]pb.WorkerClient // A cache for gRPC clients
+	strategy  SelectionStrategy
+	health    *HealthChecker
+	timeout   time.Duration               // Deadline of one dispatch, including failovers; 0 means none
+	conns     map[string]*grpc.ClientConn // A cache for gRPC connections, by worker address
 	mu        sync.Mutex
 	logger    *slog.Logger
+	tracer    trace.Tracer
 }
 
 // NewDispatcher creates a new task dispatcher.
-func NewDispatcher(discovery *WorkerDiscovery, logger *slog.Logger) domain.Dispatcher {
+// The returned Dispatcher implements both domain.Dispatcher and domain.ExecutionController,
+// and is the WorkerProber of health.
+func NewDispatcher(discovery *WorkerDiscovery, strategy SelectionStrategy, health *HealthChecker, timeout time.Duration, logger *slog.Logger) *Dispatcher {
 	return &Dispatcher{
 		discovery: discovery,
-		clients:   make(map[string]pb.WorkerClient),
+		strategy:  strategy,
+		health:    health,
+		timeout:   timeout,
+		conns:     make(map[string]*grpc.ClientConn),
 		logger:    logger.With("component", "dispatcher"),
+		tracer:    otel.Tracer("distributed-cron-dispatcher"),
 	}
 }
 
 // DispatchTask selects a worker and sends the task via gRPC.
-func (d *Dispatcher) DispatchTask(ctx context.Context, job *domain.Job) error {
+// It returns the execution ID assigned by the worker. If a worker cannot be reached, the
+// task fails over to the next candidate until every eligible worker has been tried or
+// the dispatch timeout expires.
+func (d *Dispatcher) DispatchTask(ctx context.Context, job *domain.Job, opts domain.DispatchOptions) (string, error) {
+	ctx, span := d.tracer.Start(ctx, "dispatcher.DispatchTask")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("job.name", job.Name),
+		attribute.String("dispatch.trigger", string(opts.Trigger)),
+	)
+
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+
 	// 1. Get available workers from the discovery service.
 	workers := d.discovery.GetWorkers()
 	if len(workers) == 0 {
-		return fmt.Errorf("no available workers to dispatch job %s", job.Name)
+		err := fmt.Errorf("no available workers to dispatch job %s", job.Name)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "no available workers")
+		return "", err
+	}
+
+	// Only workers whose labels satisfy the job's node selector and affinity rules may run it.
+	workers = eligibleWorkers(job, workers)
+	if len(workers) == 0 {
+		err := fmt.Errorf("%w: no worker matches the placement constraints of job %s", domain.ErrNoEligibleWorker, job.Name)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "no eligible worker")
+		return "", err
+	}
+
+	// Workers whose circuit is open are left out until their cool-down ends.
+	workers = d.availableWorkers(workers)
+	if len(workers) == 0 {
+		err := fmt.Errorf("all workers eligible for job %s are unhealthy", job.Name)
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "all eligible workers are unhealthy")
+		return "", err
+	}
+
+	// 2. Convert domain.Job to a protobuf TaskRequest.
+	taskReq, err := d.domainToProto(job)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to convert job")
+		return "", err
 	}
+	taskReq.Trigger = string(opts.Trigger)
+	taskReq.ExecutionId = opts.ExecutionID
+	taskReq.Attempt = int32(opts.Attempt)
 
-	// 2. Select a worker (simple random selection for MVP).
-	workerAddr := workers[rand.Intn(len(workers))]
+	var failed []string // Workers that could not be reached during this dispatch
+	for {
+		candidates := d.candidates(job, workers, opts, failed)
+		if len(candidates) == 0 && len(failed) == 0 {
+			err := fmt.Errorf("%w: no worker left to dispatch job %s to", domain.ErrNoCandidateWorker, job.Name)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "no candidate workers")
+			return "", err
+		}
+		if len(candidates) == 0 {
+			span.SetStatus(codes.Error, "all eligible workers are unreachable")
+			return "", fmt.Errorf("failed to dispatch job %s: all %d eligible workers are unreachable: %w", job.Name, len(failed), err)
+		}
+
+		// 3. Select a worker with the configured strategy and send it the task.
+		worker := d.strategy.Select(candidates)
+		var executionID string
+		executionID, err = d.sendTask(ctx, worker, job, taskReq)
+		if err == nil {
+			d.health.record(worker.ID, nil, false)
+		} else if herr := healthError(ctx, err); herr != nil {
+			// Other failures, such as a rejection, say nothing about the health of the worker.
+			d.health.record(worker.ID, herr, false)
+		}
+		if err == nil {
+			span.SetAttributes(
+				attribute.String("worker.id", worker.ID),
+				attribute.String("execution.id", executionID),
+				attribute.Int("dispatch.failed_attempts", len(failed)),
+			)
+			d.discovery.noteDispatched(worker.ID)
+			return executionID, nil
+		}
+
+		span.RecordError(err, trace.WithAttributes(
+			attribute.String("worker.id", worker.ID),
+			attribute.String("worker.addr", worker.Addr),
+		))
+		metrics.DispatchAttemptFailuresTotal.WithLabelValues(job.Name).Inc()
+		if !isFailoverError(err) || ctx.Err() != nil {
+			span.SetStatus(codes.Error, "failed to dispatch task")
+			return "", err
+		}
+		d.logger.Warn("worker unreachable, failing over to another worker", "job_name", job.Name, "worker_id", worker.ID, "worker_addr", worker.Addr, "error", err)
+		failed = append(failed, worker.ID)
+	}
+}
 
-	d.logger.Info("dispatching task to worker", "job_name", job.Name, "worker_addr", workerAddr)
+// sendTask calls the ExecuteTask RPC of worker.
+func (d *Dispatcher) sendTask(ctx context.Context, worker WorkerInfo, job *domain.Job, taskReq *pb.TaskRequest) (string, error) {
+	d.logger.Info("dispatching task to worker", "job_name", job.Name, "worker_id", worker.ID, "worker_addr", worker.Addr, "worker_load", worker.Load(), "trigger", taskReq.Trigger)
 
-	// 3. Get or create a gRPC client for the selected worker.
-	client, err := d.getOrCreateClient(workerAddr)
+	client, err := d.getOrCreateClient(worker.Addr)
 	if err != nil {
+		return "", err
+	}
+
+	// The context passed here will propagate trace information.
+	resp, err := client.ExecuteTask(ctx, taskReq)
+	if err != nil {
+		d.logger.Error("failed to execute task via gRPC", "job_name", job.Name, "worker_addr", worker.Addr, "error", err)
+		return "", err
+	}
+	if resp.ErrorMessage != "" {
+		return "", fmt.Errorf("%w: worker %s rejected job %s: %s", domain.ErrTaskRejected, worker.Addr, job.Name, resp.ErrorMessage)
+	}
+	return resp.ExecutionId, nil
+}
+
+// isFailoverError reports whether a dispatch that failed with err should be retried on
+// another worker. Only errors that show the task never reached the worker qualify, so that
+// a task is not started twice.
+func isFailoverError(err error) bool {
+	return status.Code(err) == grpccodes.Unavailable
+}
+
+// healthError returns err if it shows that the worker is unreachable or wedged. Once
+// ctx, the dispatch as a whole, is done, a failed attempt says nothing about the worker:
+// its deadline may have run out while an earlier worker was being tried.
+func healthError(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return nil
+	}
+	switch status.Code(err) {
+	case grpccodes.Unavailable, grpccodes.DeadlineExceeded:
 		return err
+	default:
+		return nil
 	}
+}
 
-	// 4. Convert domain.Job to a protobuf TaskRequest.
-	taskReq, err := d.domainToProto(job)
+// availableWorkers returns the workers whose circuit is not open.
+func (d *Dispatcher) availableWorkers(workers []WorkerInfo) []WorkerInfo {
+	available := make([]WorkerInfo, 0, len(workers))
+	for _, w := range workers {
+		if d.health.Available(w.ID) {
+			available = append(available, w)
+		}
+	}
+	return available
+}
+
+// candidates returns the workers the next dispatch attempt may choose from: the eligible
+// workers that have not failed during this dispatch. Retries prefer workers that have
+// not attempted the execution yet.
+func (d *Dispatcher) candidates(job *domain.Job, workers []WorkerInfo, opts domain.DispatchOptions, failed []string) []WorkerInfo {
+	remaining := excludeWorkers(workers, failed)
+	if len(opts.ExcludeWorkerIDs) == 0 || len(remaining) == 0 {
+		return remaining
+	}
+	if preferred := excludeWorkers(remaining, opts.ExcludeWorkerIDs); len(preferred) > 0 {
+		return preferred
+	}
+	d.logger.Warn("every remaining worker already attempted this execution, dispatching to any worker", "job_name", job.Name, "execution_id", opts.ExecutionID)
+	return remaining
+}
+
+// eligibleWorkers returns the workers that job accepts.
+func eligibleWorkers(job *domain.Job, workers []WorkerInfo) []WorkerInfo {
+	if len(job.NodeSelector) == 0 && len(job.Affinity) == 0 {
+		return workers
+	}
+	eligible := make([]WorkerInfo, 0, len(workers))
+	for _, w := range workers {
+		if job.AcceptsWorker(w.Labels) {
+			eligible = append(eligible, w)
+		}
+	}
+	return eligible
+}
+
+// excludeWorkers returns the workers whose ID is not in excluded.
+func excludeWorkers(workers []WorkerInfo, excluded []string) []WorkerInfo {
+	remaining := make([]WorkerInfo, 0, len(workers))
+	for _, w := range workers {
+		if !slices.Contains(excluded, w.ID) {
+			remaining = append(remaining, w)
+		}
+	}
+	return remaining
+}
+
+// CancelExecution asks the worker that runs an execution to cancel it.
+func (d *Dispatcher) CancelExecution(ctx context.Context, workerID, executionID string) error {
+	client, err := d.clientForWorker(workerID)
 	if err != nil {
 		return err
 	}
 
-	// 5. Call the worker's ExecuteTask RPC.
-	// The context passed here will propagate trace information.
-	_, err = client.ExecuteTask(ctx, taskReq)
+	resp, err := client.CancelExecution(ctx, &pb.CancelExecutionRequest{ExecutionId: executionID})
 	if err != nil {
-		d.logger.Error("failed to execute task via gRPC", "job_name", job.Name, "worker_addr", workerAddr, "error", err)
+		d.logger.Error("failed to cancel execution via gRPC", "execution_id", executionID, "worker_id", workerID, "error", err)
 		return err
 	}
+	if !resp.Cancelled {
+		return fmt.Errorf("%w: %s", domain.ErrExecutionNotRunning, resp.ErrorMessage)
+	}
 
+	d.logger.Info("cancelled execution", "execution_id", executionID, "worker_id", workerID)
+	return nil
+}
+
+// StreamExecutionLogs relays the output of a running execution from its worker to w.
+func (d *Dispatcher) StreamExecutionLogs(ctx context.Context, workerID, executionID string, follow bool, w io.Writer) error {
+	client, err := d.clientForWorker(workerID)
+	if err != nil {
+		return err
+	}
+
+	stream, err := client.StreamExecutionLogs(ctx, &pb.StreamExecutionLogsRequest{ExecutionId: executionID, Follow: follow})
+	if err != nil {
+		return err
+	}
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if status.Code(err) == grpccodes.NotFound {
+			return fmt.Errorf("%w: %s", domain.ErrExecutionNotRunning, executionID)
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(chunk.Data); err != nil {
+			return err
+		}
+	}
+}
+
+// GetExecutionOutput relays the full output of an execution from the log store of its worker to w.
+func (d *Dispatcher) GetExecutionOutput(ctx context.Context, workerID, jobName, executionID string, w io.Writer) error {
+	client, err := d.clientForWorker(workerID)
+	if err != nil {
+		return err
+	}
+
+	stream, err := client.GetExecutionOutput(ctx, &pb.GetExecutionOutputRequest{JobName: jobName, ExecutionId: executionID})
+	if err != nil {
+		return err
+	}
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if status.Code(err) == grpccodes.NotFound {
+			return fmt.Errorf("%w: %s", domain.ErrLogNotFound, executionID)
+		}
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(chunk.Data); err != nil {
+			return err
+		}
+	}
+}
+
+// clientForWorker returns a gRPC client for a registered worker, looked up by its ID.
+func (d *Dispatcher) clientForWorker(workerID string) (pb.WorkerClient, error) {
+	addr, ok := d.discovery.GetWorkerAddr(workerID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrWorkerNotFound, workerID)
+	}
+	return d.getOrCreateClient(addr)
+}
+
+// ProbeWorker checks the worker at addr with the standard gRPC health protocol.
+func (d *Dispatcher) ProbeWorker(ctx context.Context, addr string) error {
+	conn, err := d.getOrCreateConn(addr)
+	if err != nil {
+		return err
+	}
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if status.Code(err) == grpccodes.Unimplemented {
+		return nil // The worker predates the health service, but it answered
+	}
+	if err != nil {
+		return err
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("worker at %s reports %s", addr, resp.Status)
+	}
 	return nil
 }
 
 func (d *Dispatcher) getOrCreateClient(addr string) (pb.WorkerClient, error) {
+	conn, err := d.getOrCreateConn(addr)
+	if err != nil {
+		return nil, err
+	}
+	return pb.NewWorkerClient(conn), nil
+}
+
+func (d *Dispatcher) getOrCreateConn(addr string) (*grpc.ClientConn, error) {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 
-	// If client already exists in cache, return it.
-	if client, ok := d.clients[addr]; ok {
-		return client, nil
+	// If the connection already exists in cache, return it.
+	if conn, ok := d.conns[addr]; ok {
+		return conn, nil
 	}
 
 	// Otherwise, create a new gRPC connection.
@@ -89,11 +384,10 @@ func (d *Dispatcher) getOrCreateClient(addr string) (pb.WorkerClient, error) {
 		return nil, fmt.Errorf("failed to connect to worker at %s: %w", addr, err)
 	}
 
-	client := pb.NewWorkerClient(conn)
-	d.clients[addr] = client
-	d.logger.Info("created new gRPC client for worker", "addr", addr)
+	d.conns[addr] = conn
+	d.logger.Info("created new gRPC connection to worker", "addr", addr)
 
-	return client, nil
+	return conn, nil
 }
 
 // domainToProto converts a domain.Job object to a proto.TaskRequest object.
@@ -105,26 +399,71 @@ func (d *Dispatcher) domainToProto(job *domain.Job) (*pb.TaskRequest, error) {
 		ExecutorType:      string(job.ExecutorType),
 		ConcurrencyPolicy: string(job.ConcurrencyPolicy),
 		CreatedAt:         timestamppb.New(job.CreatedAt),
+		TimeZone:          job.TimeZone,
 	}
 
 	switch job.ExecutorType {
 	case domain.ExecutorTypeHTTP:
 		req.HttpExecutor = &pb.ExecutorHttp{
-			Url:    job.Executor.URL,
-			Method: job.Executor.Method,
+			Url:     job.Executor.URL,
+			Method:  job.Executor.Method,
+			Headers: job.Executor.Headers,
+			Body:    job.Executor.Body,
+		}
+		if auth := job.Executor.Auth; auth != nil {
+			req.HttpExecutor.Auth = &pb.HttpAuth{
+				Type:     string(auth.Type),
+				Username: auth.Username,
+				Password: auth.Password,
+				Token:    auth.Token,
+			}
+		}
+		for _, code := range job.Executor.ExpectedStatus {
+			req.HttpExecutor.ExpectedStatus = append(req.HttpExecutor.ExpectedStatus, int32(code))
+		}
+		if assertion := job.Executor.Assertion; assertion != nil {
+			req.HttpExecutor.Assertion = &pb.ResponseAssertion{
+				Type:       string(assertion.Type),
+				Expression: assertion.Expression,
+				Value:      assertion.Value,
+			}
 		}
 	case domain.ExecutorTypeShell:
 		req.ShellExecutor = &pb.ExecutorShell{
-			Command: job.Executor.Command,
+			Command:    job.Executor.Command,
+			Env:        job.Executor.Env,
+			WorkingDir: job.Executor.WorkingDir,
+			User:       job.Executor.User,
+			Group:      job.Executor.Group,
+		}
+		if job.Executor.KillGracePeriod > 0 {
+			req.ShellExecutor.KillGracePeriod = job.Executor.KillGracePeriod.String()
+		}
+		if limits := job.Executor.Limits; limits != nil {
+			req.ShellExecutor.Limits = &pb.ResourceLimits{
+				Cpu:      limits.CPU,
+				MemoryMb: limits.MemoryMB,
+				Pids:     limits.Pids,
+			}
 		}
 	default:
 		return nil, fmt.Errorf("unknown executor type: %s", job.ExecutorType)
 	}
 
+	if job.Timeout > 0 {
+		req.Timeout = job.Timeout.String()
+	}
+
 	if job.RetryPolicy != nil {
 		req.RetryPolicy = &pb.RetryPolicy{
-			MaxRetries: int32(job.RetryPolicy.MaxRetries),
-			Backoff:    job.RetryPolicy.Backoff.String(),
+			MaxRetries:             int32(job.RetryPolicy.MaxRetries),
+			Backoff:                job.RetryPolicy.Backoff.String(),
+			Multiplier:             job.RetryPolicy.Multiplier,
+			Jitter:                 job.RetryPolicy.Jitter,
+			RetryOnDifferentWorker: job.RetryPolicy.RetryOnDifferentWorker,
+		}
+		if job.RetryPolicy.MaxBackoff > 0 {
+			req.RetryPolicy.MaxBackoff = job.RetryPolicy.MaxBackoff.String()
 		}
 	}
 
@@ -0,0 +1,230 @@
+// internal/master/health.go
+package master
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"distributed-cron/internal/domain"
+
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
+)
+
+// WorkerProber checks whether the worker at addr is able to take tasks.
+type WorkerProber interface {
+	ProbeWorker(ctx context.Context, addr string) error
+}
+
+// HealthChecker keeps a circuit breaker per worker. Failures of periodic health probes
+// and of dispatches count against a worker; after threshold consecutive failures its
+// circuit opens and the worker is left out of selection for the cool-down period. The
+// first probe or dispatch after the cool-down closes the circuit again if it succeeds.
+type HealthChecker struct {
+	discovery *WorkerDiscovery
+	interval  time.Duration // Time between probes of all workers
+	timeout   time.Duration // Deadline of one probe
+	threshold int           // Consecutive failures that open the circuit
+	cooldown  time.Duration // How long an open circuit keeps the worker out of selection
+	mu        sync.Mutex
+	states    map[string]*domain.WorkerHealth // map of workerID -> health
+	logger    *slog.Logger
+}
+
+// NewHealthChecker creates a new health checker for the workers found by discovery.
+// The returned HealthChecker implements domain.WorkerDirectory.
+func NewHealthChecker(discovery *WorkerDiscovery, interval, timeout time.Duration, threshold int, cooldown time.Duration, logger *slog.Logger) *HealthChecker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &HealthChecker{
+		discovery: discovery,
+		interval:  interval,
+		timeout:   timeout,
+		threshold: threshold,
+		cooldown:  cooldown,
+		states:    make(map[string]*domain.WorkerHealth),
+		logger:    logger.With("component", "health-checker"),
+	}
+}
+
+// Run probes every registered worker each interval until ctx is cancelled.
+func (h *HealthChecker) Run(ctx context.Context, prober WorkerProber) {
+	if h.interval <= 0 {
+		h.logger.Info("worker health probing disabled")
+		return
+	}
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			h.probeAll(ctx, prober)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// probeAll probes the registered workers concurrently, skipping those whose circuit is
+// open, and forgets the workers that are no longer registered.
+func (h *HealthChecker) probeAll(ctx context.Context, prober WorkerProber) {
+	workers := h.discovery.GetWorkers()
+	h.forgetDeregistered(workers)
+
+	var wg sync.WaitGroup
+	for _, w := range workers {
+		if !h.Available(w.ID) {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			probeCtx, cancel := context.WithTimeout(ctx, h.timeout)
+			defer cancel()
+			err := prober.ProbeWorker(probeCtx, w.Addr)
+			if ctx.Err() != nil {
+				return
+			}
+			h.record(w.ID, err, true)
+		}()
+	}
+	wg.Wait()
+}
+
+func (h *HealthChecker) forgetDeregistered(workers []WorkerInfo) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for id := range h.states {
+		if !slices.ContainsFunc(workers, func(w WorkerInfo) bool { return w.ID == id }) {
+			delete(h.states, id)
+		}
+	}
+}
+
+// Available reports whether workerID may be selected, i.e. its circuit is not open.
+func (h *HealthChecker) Available(workerID string) bool {
+	return h.health(workerID).Circuit != domain.CircuitOpen
+}
+
+// health returns a copy of the health of workerID, moving an open circuit whose
+// cool-down has ended to half-open.
+func (h *HealthChecker) health(workerID string) domain.WorkerHealth {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	st, ok := h.states[workerID]
+	if !ok {
+		return domain.WorkerHealth{Circuit: domain.CircuitClosed}
+	}
+	if st.Circuit == domain.CircuitOpen && !time.Now().Before(st.OpenUntil) {
+		st.Circuit = domain.CircuitHalfOpen
+		st.OpenUntil = time.Time{}
+	}
+	return *st
+}
+
+// record updates the circuit of workerID with the outcome of a probe or a dispatch.
+func (h *HealthChecker) record(workerID string, err error, probe bool) {
+	h.health(workerID) // Let an expired cool-down turn into half-open first
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	st, ok := h.states[workerID]
+	if !ok {
+		st = &domain.WorkerHealth{Circuit: domain.CircuitClosed}
+		h.states[workerID] = st
+	}
+	if probe {
+		st.LastProbeAt = time.Now()
+	}
+
+	if err == nil {
+		if st.Circuit != domain.CircuitClosed {
+			h.logger.Info("worker recovered, closing circuit", "worker_id", workerID)
+		}
+		st.Circuit = domain.CircuitClosed
+		st.ConsecutiveFailures = 0
+		st.LastError = ""
+		return
+	}
+
+	st.ConsecutiveFailures++
+	st.LastError = err.Error()
+	if st.Circuit == domain.CircuitHalfOpen || (st.Circuit == domain.CircuitClosed && st.ConsecutiveFailures >= h.threshold) {
+		st.Circuit = domain.CircuitOpen
+		st.OpenUntil = time.Now().Add(h.cooldown)
+		h.logger.Warn("opening circuit of unhealthy worker", "worker_id", workerID, "consecutive_failures", st.ConsecutiveFailures, "cooldown", h.cooldown, "error", err)
+	}
+}
+
+// ListWorkers returns the registered workers with their health, ordered by ID. Leases
+// are looked up concurrently; a worker whose lease is gone is left out, and one whose
+// lease cannot be looked up is listed without its lease TTL and last heartbeat.
+func (h *HealthChecker) ListWorkers(ctx context.Context) ([]domain.WorkerStatus, error) {
+	workers := h.discovery.GetWorkers()
+	slices.SortFunc(workers, func(a, b WorkerInfo) int { return strings.Compare(a.ID, b.ID) })
+
+	statuses := make([]domain.WorkerStatus, len(workers))
+	registered := make([]bool, len(workers))
+	var wg sync.WaitGroup
+	for i, w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i], registered[i] = h.status(ctx, w)
+		}()
+	}
+	wg.Wait()
+
+	listed := statuses[:0]
+	for i, status := range statuses {
+		if registered[i] {
+			listed = append(listed, status)
+		}
+	}
+	return listed, nil
+}
+
+// GetWorker returns a registered worker with its health.
+func (h *HealthChecker) GetWorker(ctx context.Context, workerID string) (*domain.WorkerStatus, error) {
+	for _, w := range h.discovery.GetWorkers() {
+		if w.ID == workerID {
+			if status, ok := h.status(ctx, w); ok {
+				return &status, nil
+			}
+			break
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", domain.ErrWorkerNotFound, workerID)
+}
+
+// status returns the status of w, and false if its lease no longer exists, i.e. the
+// worker is gone and discovery has yet to see it deregister.
+func (h *HealthChecker) status(ctx context.Context, w WorkerInfo) (domain.WorkerStatus, bool) {
+	ttl, renewedAt, err := h.discovery.leaseInfo(ctx, w)
+	if errors.Is(err, rpctypes.ErrLeaseNotFound) {
+		return domain.WorkerStatus{}, false
+	}
+	if err != nil {
+		h.logger.Warn("failed to get worker lease, listing it without lease info", "worker_id", w.ID, "error", err)
+	}
+	return domain.WorkerStatus{
+		ID:              w.ID,
+		Addr:            w.Addr,
+		Running:         w.Running,
+		Capacity:        w.Capacity,
+		Labels:          w.Labels,
+		Health:          h.health(w.ID),
+		RegisteredAt:    w.RegisteredAt,
+		LeaseTTLSeconds: ttl,
+		LastHeartbeat:   renewedAt,
+	}, true
+}
@@ -0,0 +1,108 @@
+package master
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"distributed-cron/internal/domain"
+)
+
+func TestHealthCheckerCircuit(t *testing.T) {
+	errProbe := errors.New("connection refused")
+
+	// A step records a probe outcome, or with expire set ends the cool-down of an open
+	// circuit, and then checks the circuit of the worker.
+	type step struct {
+		err          error
+		expire       bool
+		wantCircuit  domain.CircuitState
+		wantFailures int
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "opens after threshold consecutive failures",
+			steps: []step{
+				{err: errProbe, wantCircuit: domain.CircuitClosed, wantFailures: 1},
+				{err: errProbe, wantCircuit: domain.CircuitClosed, wantFailures: 2},
+				{err: errProbe, wantCircuit: domain.CircuitOpen, wantFailures: 3},
+			},
+		},
+		{
+			name: "a success resets the failure count",
+			steps: []step{
+				{err: errProbe, wantCircuit: domain.CircuitClosed, wantFailures: 1},
+				{err: errProbe, wantCircuit: domain.CircuitClosed, wantFailures: 2},
+				{wantCircuit: domain.CircuitClosed},
+				{err: errProbe, wantCircuit: domain.CircuitClosed, wantFailures: 1},
+			},
+		},
+		{
+			name: "stays open until the cool-down ends",
+			steps: []step{
+				{err: errProbe, wantCircuit: domain.CircuitClosed, wantFailures: 1},
+				{err: errProbe, wantCircuit: domain.CircuitClosed, wantFailures: 2},
+				{err: errProbe, wantCircuit: domain.CircuitOpen, wantFailures: 3},
+				{err: errProbe, wantCircuit: domain.CircuitOpen, wantFailures: 4},
+				{expire: true, wantCircuit: domain.CircuitHalfOpen, wantFailures: 4},
+			},
+		},
+		{
+			name: "half-open closes on success",
+			steps: []step{
+				{err: errProbe, wantCircuit: domain.CircuitClosed, wantFailures: 1},
+				{err: errProbe, wantCircuit: domain.CircuitClosed, wantFailures: 2},
+				{err: errProbe, wantCircuit: domain.CircuitOpen, wantFailures: 3},
+				{expire: true, wantCircuit: domain.CircuitHalfOpen, wantFailures: 3},
+				{wantCircuit: domain.CircuitClosed},
+			},
+		},
+		{
+			name: "half-open reopens on the first failure",
+			steps: []step{
+				{err: errProbe, wantCircuit: domain.CircuitClosed, wantFailures: 1},
+				{err: errProbe, wantCircuit: domain.CircuitClosed, wantFailures: 2},
+				{err: errProbe, wantCircuit: domain.CircuitOpen, wantFailures: 3},
+				{expire: true, wantCircuit: domain.CircuitHalfOpen, wantFailures: 3},
+				{err: errProbe, wantCircuit: domain.CircuitOpen, wantFailures: 4},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthChecker(nil, time.Second, time.Second, 3, time.Hour, slog.New(slog.DiscardHandler))
+			for i, s := range tt.steps {
+				if s.expire {
+					h.mu.Lock()
+					h.states["w1"].OpenUntil = time.Now().Add(-time.Second)
+					h.mu.Unlock()
+				} else {
+					h.record("w1", s.err, true)
+				}
+
+				got := h.health("w1")
+				if got.Circuit != s.wantCircuit || got.ConsecutiveFailures != s.wantFailures {
+					t.Fatalf("step %d: health() = %s with %d failures, want %s with %d", i, got.Circuit, got.ConsecutiveFailures, s.wantCircuit, s.wantFailures)
+				}
+				if want := s.wantCircuit != domain.CircuitOpen; h.Available("w1") != want {
+					t.Errorf("step %d: Available() = %v, want %v", i, !want, want)
+				}
+			}
+		})
+	}
+}
+
+func TestHealthCheckerUnknownWorkerIsAvailable(t *testing.T) {
+	h := NewHealthChecker(nil, time.Second, time.Second, 1, time.Hour, slog.New(slog.DiscardHandler))
+	if got := h.health("w1").Circuit; got != domain.CircuitClosed {
+		t.Errorf("health() = %s, want %s", got, domain.CircuitClosed)
+	}
+	if !h.Available("w1") {
+		t.Errorf("Available() = false, want true")
+	}
+}
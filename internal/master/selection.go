@@ -0,0 +1,95 @@
+// internal/master/selection.go
+package master
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// Worker selection strategies, as named in the configuration.
+const (
+	StrategyLeastLoaded = "least_loaded"
+	StrategyRoundRobin  = "round_robin"
+	StrategyPowerOfTwo  = "power_of_two"
+	StrategyRandom      = "random"
+)
+
+// SelectionStrategy picks the worker an execution is dispatched to.
+type SelectionStrategy interface {
+	// Select returns one of workers, which is never empty.
+	Select(workers []WorkerInfo) WorkerInfo
+}
+
+// NewSelectionStrategy returns the strategy with the given name.
+func NewSelectionStrategy(name string) (SelectionStrategy, error) {
+	switch name {
+	case StrategyLeastLoaded:
+		return leastLoaded{}, nil
+	case StrategyRoundRobin:
+		return &roundRobin{}, nil
+	case StrategyPowerOfTwo:
+		return powerOfTwo{}, nil
+	case StrategyRandom:
+		return random{}, nil
+	default:
+		return nil, fmt.Errorf("unknown worker selection strategy %q", name)
+	}
+}
+
+// leastLoaded picks the worker with the lowest load, and a random one among equals.
+type leastLoaded struct{}
+
+func (leastLoaded) Select(workers []WorkerInfo) WorkerInfo {
+	var candidates []WorkerInfo
+	for _, w := range workers {
+		switch {
+		case len(candidates) == 0 || w.Load() < candidates[0].Load():
+			candidates = append(candidates[:0], w)
+		case w.Load() == candidates[0].Load():
+			candidates = append(candidates, w)
+		}
+	}
+	return candidates[rand.Intn(len(candidates))]
+}
+
+// roundRobin cycles through the workers in order of their IDs.
+type roundRobin struct {
+	next atomic.Uint64
+}
+
+func (r *roundRobin) Select(workers []WorkerInfo) WorkerInfo {
+	sorted := slices.SortedFunc(slices.Values(workers), func(a, b WorkerInfo) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return sorted[(r.next.Add(1)-1)%uint64(len(sorted))]
+}
+
+// powerOfTwo picks two workers at random and takes the less loaded one. It spreads load
+// almost as well as leastLoaded while being less prone to every master sending a burst
+// to the same worker on stale load figures.
+type powerOfTwo struct{}
+
+func (powerOfTwo) Select(workers []WorkerInfo) WorkerInfo {
+	if len(workers) == 1 {
+		return workers[0]
+	}
+	i := rand.Intn(len(workers))
+	j := rand.Intn(len(workers) - 1)
+	if j >= i {
+		j++
+	}
+	if workers[j].Load() < workers[i].Load() {
+		return workers[j]
+	}
+	return workers[i]
+}
+
+// random picks any worker.
+type random struct{}
+
+func (random) Select(workers []WorkerInfo) WorkerInfo {
+	return workers[rand.Intn(len(workers))]
+}
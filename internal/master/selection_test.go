@@ -0,0 +1,127 @@
+package master
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+// picks runs Select n times and counts the picks by worker ID.
+func picks(strategy SelectionStrategy, workers []WorkerInfo, n int) map[string]int {
+	counts := make(map[string]int)
+	for range n {
+		counts[strategy.Select(workers).ID]++
+	}
+	return counts
+}
+
+func TestWorkerLoad(t *testing.T) {
+	tests := []struct {
+		name   string
+		worker WorkerInfo
+		want   float64
+	}{
+		{name: "share of capacity", worker: WorkerInfo{Running: 3, Capacity: 4}, want: 0.75},
+		{name: "over capacity", worker: WorkerInfo{Running: 6, Capacity: 4}, want: 1.5},
+		{name: "running count without capacity", worker: WorkerInfo{Running: 3}, want: 3},
+		{name: "idle", worker: WorkerInfo{Capacity: 8}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.worker.Load(); got != tt.want {
+				t.Errorf("Load() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewSelectionStrategy(t *testing.T) {
+	for _, name := range []string{StrategyLeastLoaded, StrategyRoundRobin, StrategyPowerOfTwo, StrategyRandom} {
+		if _, err := NewSelectionStrategy(name); err != nil {
+			t.Errorf("NewSelectionStrategy(%q) error = %v", name, err)
+		}
+	}
+	if _, err := NewSelectionStrategy("busiest"); err == nil {
+		t.Error(`NewSelectionStrategy("busiest") error = nil, want an unknown strategy error`)
+	}
+}
+
+func TestSelectionStrategies(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		workers  []WorkerInfo
+		want     []string // Every worker that must be picked, and the only ones that may be
+	}{
+		{
+			name:     "least loaded picks the lowest share of capacity",
+			strategy: StrategyLeastLoaded,
+			workers:  []WorkerInfo{{ID: "a", Running: 2, Capacity: 4}, {ID: "b", Running: 3, Capacity: 10}, {ID: "c", Running: 1, Capacity: 1}},
+			want:     []string{"b"},
+		},
+		{
+			name:     "least loaded spreads over equally loaded workers",
+			strategy: StrategyLeastLoaded,
+			workers:  []WorkerInfo{{ID: "a", Running: 1, Capacity: 2}, {ID: "b", Running: 2}, {ID: "c", Running: 2, Capacity: 4}},
+			want:     []string{"a", "c"},
+		},
+		{
+			name:     "power of two never picks the busiest",
+			strategy: StrategyPowerOfTwo,
+			workers:  []WorkerInfo{{ID: "a", Running: 1}, {ID: "b", Running: 9}, {ID: "c", Running: 2}},
+			want:     []string{"a", "c"},
+		},
+		{
+			name:     "power of two with two workers picks the less loaded",
+			strategy: StrategyPowerOfTwo,
+			workers:  []WorkerInfo{{ID: "a", Running: 5}, {ID: "b", Running: 1}},
+			want:     []string{"b"},
+		},
+		{
+			name:     "power of two with one worker",
+			strategy: StrategyPowerOfTwo,
+			workers:  []WorkerInfo{{ID: "a", Running: 5}},
+			want:     []string{"a"},
+		},
+		{
+			name:     "random picks any worker",
+			strategy: StrategyRandom,
+			workers:  []WorkerInfo{{ID: "a", Running: 5}, {ID: "b"}, {ID: "c", Running: 1}},
+			want:     []string{"a", "b", "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			strategy, err := NewSelectionStrategy(tt.strategy)
+			if err != nil {
+				t.Fatalf("NewSelectionStrategy() error = %v", err)
+			}
+			got := slices.Sorted(maps.Keys(picks(strategy, tt.workers, 1000)))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("picked %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundRobinCyclesInIDOrder(t *testing.T) {
+	strategy, err := NewSelectionStrategy(StrategyRoundRobin)
+	if err != nil {
+		t.Fatalf("NewSelectionStrategy() error = %v", err)
+	}
+	workers := []WorkerInfo{{ID: "c"}, {ID: "a", Running: 9}, {ID: "b"}}
+
+	var got []string
+	for i := range 7 {
+		// The order workers are listed in changes between calls, as it does in discovery.
+		slices.Reverse(workers)
+		got = append(got, strategy.Select(workers).ID)
+		if i == 3 {
+			workers = append(workers, WorkerInfo{ID: "d"})
+		}
+	}
+	if want := []string{"a", "b", "c", "a", "a", "b", "c"}; !slices.Equal(got, want) {
+		t.Errorf("picked %v, want %v", got, want)
+	}
+}
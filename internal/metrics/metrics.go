@@ -25,6 +25,24 @@ var (
 		[]string{"job_name", "status"}, // 按任务名、执行状态 (success/failed) 分类
 	)
 
+	// ExecutionRecordsPrunedTotal 记录被保留策略清理的执行记录总数
+	ExecutionRecordsPrunedTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "execution_records_pruned_total",
+			Help: "Total number of execution records deleted by the history retention policy.",
+		},
+		[]string{"job_name"},
+	)
+
+	// DispatchAttemptFailuresTotal 记录失败的派发尝试总数 (Worker 不可达时会转投其他 Worker)
+	DispatchAttemptFailuresTotal = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "dispatch_attempt_failures_total",
+			Help: "Total number of attempts to send a task to a worker that failed.",
+		},
+		[]string{"job_name"},
+	)
+
 	// IsLeader 标记当前节点是否为 Leader
 	IsLeader = promauto.NewGaugeVec(
 		prometheus.GaugeOpts{
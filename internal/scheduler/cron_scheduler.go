@@ -3,7 +3,10 @@ package scheduler
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
+	"sync"
+	"time"
 
 	"distributed-cron/internal/domain"
 
@@ -13,30 +16,116 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+// scheduleParser mirrors the parser installed by cron.WithSeconds, so every
+// job is parsed the same way regardless of its time zone.
+var scheduleParser = cron.NewParser(
+	cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor,
+)
+
+// parseSchedule parses the job's cron expression and pins it to the job's time zone.
+// Fire times are computed by cron.SpecSchedule in that location, so wall-clock
+// schedules follow the zone's DST transitions rather than the master's local time:
+// a time inside a spring-forward gap does not exist and is skipped that day, and a
+// time inside a fall-back repeat fires once, see wallClockSchedule.
+func parseSchedule(job *domain.Job) (cron.Schedule, error) {
+	schedule, err := scheduleParser.Parse(job.CronExpr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", job.CronExpr, err)
+	}
+
+	var loc *time.Location
+	if job.TimeZone != "" {
+		loc, err = time.LoadLocation(job.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", job.TimeZone, err)
+		}
+	}
+	// Descriptors such as "@every 1m" yield a ConstantDelaySchedule, which is zone independent.
+	spec, ok := schedule.(*cron.SpecSchedule)
+	if !ok {
+		return schedule, nil
+	}
+	if loc != nil {
+		spec.Location = loc
+	}
+	return wallClockSchedule{spec}, nil
+}
+
+const (
+	// cronStarBit marks a field of a cron.SpecSchedule that was given as "*".
+	cronStarBit = 1 << 63
+	// dstLookback is how far back wallClockSchedule looks for a fall-back transition.
+	// Zones shift by at most a few hours, so a repeat never lasts longer.
+	dstLookback = 12 * time.Hour
+)
+
+// wallClockSchedule fires a schedule with fixed hours once per wall-clock time. Across a
+// fall-back transition cron.SpecSchedule fires such a schedule in both offsets of the
+// repeated hour, e.g. a daily 01:30 job twice. Schedules with "*" hours keep firing by
+// elapsed time through the repeat.
+type wallClockSchedule struct {
+	*cron.SpecSchedule
+}
+
+func (s wallClockSchedule) Next(t time.Time) time.Time {
+	for {
+		next := s.SpecSchedule.Next(t)
+		if next.IsZero() || s.Hour&cronStarBit != 0 || !s.repeated(next) {
+			return next
+		}
+		t = next
+	}
+}
+
+// repeated reports whether the wall-clock time of t already occurred at an earlier
+// instant, before the zone fell back.
+func (s wallClockSchedule) repeated(t time.Time) bool {
+	local := t.In(s.Location)
+	_, offset := local.Zone()
+	_, before := local.Add(-dstLookback).Zone()
+	if before <= offset {
+		return false
+	}
+	earlier := local.Add(-time.Duration(before-offset) * time.Second)
+	return earlier.Format(time.DateTime) == local.Format(time.DateTime)
+}
+
 // cronScheduler's responsibility is now purely to trigger tasks at the right time.
 type cronScheduler struct {
 	cron       *cron.Cron
 	dispatcher domain.Dispatcher // Depends on the dispatcher interface
+	fireTimes  domain.FireTimeRepository
 	jobs       map[string]cron.EntryID
+	mu         sync.Mutex
+	now        func() time.Time // Clock used to detect missed runs
 	logger     *slog.Logger
 	tracer     trace.Tracer
 }
 
 // NewCronScheduler now takes a dispatcher instead of an executor and locker.
-func NewCronScheduler(dispatcher domain.Dispatcher, logger *slog.Logger) domain.Schedular {
+// Fire times are persisted through fireTimes so that runs missed across a
+// leader failover can be caught up according to each job's MisfirePolicy.
+func NewCronScheduler(dispatcher domain.Dispatcher, fireTimes domain.FireTimeRepository, logger *slog.Logger) domain.Schedular {
 	c := cron.New(cron.WithSeconds())
 	return &cronScheduler{
 		cron:       c,
 		dispatcher: dispatcher,
+		fireTimes:  fireTimes,
 		jobs:       make(map[string]cron.EntryID),
+		now:        time.Now,
 		logger:     logger.With("component", "cron-scheduler"),
 		tracer:     otel.Tracer("distributed-cron-scheduler"),
 	}
 }
 
 func (s *cronScheduler) Start(ctx context.Context) error {
+	// Work out what was missed before the cron starts firing and overwriting fire times.
+	misfires := s.collectMisfires(ctx)
+
 	s.logger.Info("cron scheduler started")
 	s.cron.Start()
+	go s.dispatchMisfires(ctx, misfires)
+
 	<-ctx.Done()
 	s.logger.Info("cron scheduler stopping...")
 	stopCtx := s.cron.Stop()
@@ -51,48 +140,168 @@ func (s *cronScheduler) Stop() {
 
 // AddJob adds a job to the scheduler.
 func (s *cronScheduler) AddJob(job *domain.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if entryID, ok := s.jobs[job.Name]; ok {
 		s.cron.Remove(entryID)
+		delete(s.jobs, job.Name)
 	}
 
-	jobWrapper := &cronJobWrapper{
-		job:        job,
-		dispatcher: s.dispatcher,
-		logger:     s.logger.With("job_name", job.Name),
-		tracer:     s.tracer,
+	if job.Paused {
+		s.logger.Info("job is paused, not scheduling", "job_name", job.Name)
+		return nil
 	}
 
-	entryID, err := s.cron.AddJob(job.CronExpr, jobWrapper)
+	schedule, err := parseSchedule(job)
 	if err != nil {
 		s.logger.Error("failed to add job to cron", "job_name", job.Name, "error", err)
 		return err
 	}
 
+	jobWrapper := &cronJobWrapper{
+		job:        job,
+		schedule:   schedule,
+		dispatcher: s.dispatcher,
+		fireTimes:  s.fireTimes,
+		logger:     s.logger.With("job_name", job.Name),
+		tracer:     s.tracer,
+	}
+
+	entryID := s.cron.Schedule(schedule, jobWrapper)
 	s.jobs[job.Name] = entryID
-	s.logger.Info("added job to scheduler", "job_name", job.Name, "schedule", job.CronExpr)
+	s.logger.Info("added job to scheduler", "job_name", job.Name, "schedule", job.CronExpr, "time_zone", job.TimeZone)
 	return nil
 }
 
 // RemoveJob removes a job from the scheduler.
 func (s *cronScheduler) RemoveJob(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if entryID, ok := s.jobs[name]; ok {
 		s.cron.Remove(entryID)
 		delete(s.jobs, name)
 		s.logger.Info("removed job from scheduler", "job_name", name)
 	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.fireTimes.DeleteLastFireTime(ctx, name); err != nil {
+		s.logger.Warn("failed to delete last fire time", "job_name", name, "error", err)
+	}
 	return nil
 }
 
+// Clear unschedules every job. Fire times are kept, so the jobs that are scheduled again
+// afterwards can still catch up on missed runs.
+func (s *cronScheduler) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for name, entryID := range s.jobs {
+		s.cron.Remove(entryID)
+		delete(s.jobs, name)
+	}
+	s.logger.Info("cleared all jobs from scheduler")
+}
+
+// misfire holds the runs of a job that were due while no leader was scheduling it.
+type misfire struct {
+	wrapper     *cronJobWrapper
+	scheduledAt []time.Time
+}
+
+// collectMisfires compares every scheduled job's persisted last fire time with the
+// current time and returns the runs its MisfirePolicy asks to catch up.
+func (s *cronScheduler) collectMisfires(ctx context.Context) []misfire {
+	now := s.now()
+	s.mu.Lock()
+	wrappers := make([]*cronJobWrapper, 0, len(s.jobs))
+	for _, entryID := range s.jobs {
+		if w, ok := s.cron.Entry(entryID).Job.(*cronJobWrapper); ok {
+			wrappers = append(wrappers, w)
+		}
+	}
+	s.mu.Unlock()
+
+	var misfires []misfire
+	for _, w := range wrappers {
+		var limit int
+		switch w.job.MisfirePolicy {
+		case domain.MisfirePolicyFireOnce:
+			limit = 1
+		case domain.MisfirePolicyFireAll:
+			limit = w.job.MaxMisfireRuns
+			if limit == 0 {
+				limit = domain.DefaultMaxMisfireRuns
+			}
+		default:
+			continue
+		}
+
+		lastFiredAt, err := s.fireTimes.GetLastFireTime(ctx, w.job.Name)
+		if err != nil {
+			s.logger.Error("failed to load last fire time, skipping misfire check", "job_name", w.job.Name, "error", err)
+			continue
+		}
+		if lastFiredAt.IsZero() {
+			continue
+		}
+		// Never replay runs that were due before the job's current definition was saved.
+		if w.job.UpdatedAt.After(lastFiredAt) {
+			lastFiredAt = w.job.UpdatedAt
+		}
+
+		missed := missedFireTimes(w.schedule, lastFiredAt, now, limit)
+		if len(missed) > 0 {
+			s.logger.Warn("detected missed runs", "job_name", w.job.Name, "last_fired_at", lastFiredAt,
+				"missed_runs", len(missed), "misfire_policy", w.job.MisfirePolicy)
+			misfires = append(misfires, misfire{wrapper: w, scheduledAt: missed})
+		}
+	}
+	return misfires
+}
+
+// dispatchMisfires dispatches the runs collected by collectMisfires, oldest first.
+func (s *cronScheduler) dispatchMisfires(ctx context.Context, misfires []misfire) {
+	for _, m := range misfires {
+		for _, scheduledAt := range m.scheduledAt {
+			if ctx.Err() != nil {
+				return
+			}
+			m.wrapper.dispatchMisfire(ctx, scheduledAt)
+		}
+	}
+}
+
+// missedFireTimes returns the fire times of schedule in (after, now], keeping at most
+// the latest limit of them.
+func missedFireTimes(schedule cron.Schedule, after, now time.Time, limit int) []time.Time {
+	var missed []time.Time
+	for t := schedule.Next(after); !t.IsZero() && !t.After(now); t = schedule.Next(t) {
+		missed = append(missed, t)
+		if len(missed) > limit {
+			missed = missed[1:]
+		}
+	}
+	return missed
+}
+
 // cronJobWrapper now only calls the dispatcher.
 type cronJobWrapper struct {
 	job        *domain.Job
+	schedule   cron.Schedule
 	dispatcher domain.Dispatcher
+	fireTimes  domain.FireTimeRepository
 	logger     *slog.Logger
 	tracer     trace.Tracer
 }
 
 // Run is called by the cron library. Its only job is to dispatch the task.
 func (w *cronJobWrapper) Run() {
+	firedAt := time.Now()
+
 	// Start a new trace for this background job execution.
 	ctx, span := w.tracer.Start(context.Background(), "scheduler.Dispatch",
 		trace.WithAttributes(
@@ -101,9 +310,50 @@ func (w *cronJobWrapper) Run() {
 		))
 	defer span.End()
 
+	w.recordFireTime(ctx, firedAt)
+
 	w.logger.Info("dispatching job")
-	if err := w.dispatcher.DispatchTask(ctx, w.job); err != nil {
+	executionID, err := w.dispatcher.DispatchTask(ctx, w.job, domain.DispatchOptions{Trigger: domain.TriggerSchedule})
+	if err != nil {
 		w.logger.Error("failed to dispatch job", "error", err)
 		span.RecordError(err)
+		return
+	}
+	span.SetAttributes(attribute.String("execution.id", executionID))
+}
+
+// dispatchMisfire dispatches a single run that was due at scheduledAt but never fired.
+func (w *cronJobWrapper) dispatchMisfire(ctx context.Context, scheduledAt time.Time) {
+	ctx, span := w.tracer.Start(ctx, "scheduler.DispatchMisfire",
+		trace.WithAttributes(
+			attribute.String("job.name", w.job.Name),
+			attribute.String("job.id", w.job.ID),
+			attribute.String("misfire.scheduled_at", scheduledAt.Format(time.RFC3339)),
+		))
+	defer span.End()
+
+	w.logger.Info("dispatching missed run", "scheduled_at", scheduledAt)
+	executionID, err := w.dispatcher.DispatchTask(ctx, w.job, domain.DispatchOptions{Trigger: domain.TriggerMisfire})
+	if err != nil {
+		w.logger.Error("failed to dispatch missed run", "scheduled_at", scheduledAt, "error", err)
+		span.RecordError(err)
+	} else {
+		span.SetAttributes(attribute.String("execution.id", executionID))
+	}
+	w.recordFireTime(ctx, scheduledAt)
+}
+
+// recordFireTime persists firedAt unless a later fire time has already been stored.
+func (w *cronJobWrapper) recordFireTime(ctx context.Context, firedAt time.Time) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	last, err := w.fireTimes.GetLastFireTime(ctx, w.job.Name)
+	if err == nil && last.After(firedAt) {
+		return
+	}
+	if err := w.fireTimes.SaveLastFireTime(ctx, w.job.Name, firedAt); err != nil {
+		w.logger.Error("failed to record fire time", "error", err)
+		trace.SpanFromContext(ctx).RecordError(err)
 	}
 }
@@ -0,0 +1,232 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"distributed-cron/internal/domain"
+)
+
+func utc(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("invalid time %q: %v", value, err)
+	}
+	return parsed.UTC()
+}
+
+func utcs(t *testing.T, values ...string) []time.Time {
+	t.Helper()
+	times := make([]time.Time, len(values))
+	for i, v := range values {
+		times[i] = utc(t, v)
+	}
+	return times
+}
+
+func TestParseScheduleNext(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeZone string
+		cronExpr string
+		from     string
+		want     []string // Consecutive fire times after from
+	}{
+		{
+			name:     "Shanghai has no DST",
+			timeZone: "Asia/Shanghai",
+			cronExpr: "0 0 9 * * *",
+			from:     "2024-03-09T12:00:00Z",
+			want:     []string{"2024-03-10T01:00:00Z", "2024-03-11T01:00:00Z"},
+		},
+		{
+			name:     "Berlin keeps the wall-clock time across spring forward",
+			timeZone: "Europe/Berlin",
+			cronExpr: "0 0 9 * * *",
+			from:     "2024-03-30T00:00:00Z",
+			want:     []string{"2024-03-30T08:00:00Z", "2024-03-31T07:00:00Z"},
+		},
+		{
+			name:     "Berlin skips a time inside the spring-forward gap",
+			timeZone: "Europe/Berlin",
+			cronExpr: "0 30 2 * * *",
+			from:     "2024-03-30T12:00:00Z",
+			want:     []string{"2024-04-01T00:30:00Z", "2024-04-02T00:30:00Z"},
+		},
+		{
+			name:     "Berlin fires a time inside the fall-back repeat once",
+			timeZone: "Europe/Berlin",
+			cronExpr: "0 30 2 * * *",
+			from:     "2024-10-26T12:00:00Z",
+			want:     []string{"2024-10-27T00:30:00Z", "2024-10-28T01:30:00Z"},
+		},
+		{
+			name:     "New York skips a time inside the spring-forward gap",
+			timeZone: "America/New_York",
+			cronExpr: "0 30 2 * * *",
+			from:     "2024-03-09T12:00:00Z",
+			want:     []string{"2024-03-11T06:30:00Z", "2024-03-12T06:30:00Z"},
+		},
+		{
+			name:     "New York fires a time inside the fall-back repeat once",
+			timeZone: "America/New_York",
+			cronExpr: "0 30 1 * * *",
+			from:     "2024-11-02T12:00:00Z",
+			want:     []string{"2024-11-03T05:30:00Z", "2024-11-04T06:30:00Z"},
+		},
+		{
+			name:     "New York keeps firing every half hour through the fall-back repeat",
+			timeZone: "America/New_York",
+			cronExpr: "0 */30 * * * *",
+			from:     "2024-11-03T04:45:00Z",
+			want:     []string{"2024-11-03T05:00:00Z", "2024-11-03T05:30:00Z", "2024-11-03T06:00:00Z", "2024-11-03T06:30:00Z"},
+		},
+		{
+			name:     "New York steps over the spring-forward gap every half hour",
+			timeZone: "America/New_York",
+			cronExpr: "0 */30 * * * *",
+			from:     "2024-03-10T06:15:00Z",
+			want:     []string{"2024-03-10T06:30:00Z", "2024-03-10T07:00:00Z", "2024-03-10T07:30:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseSchedule(&domain.Job{CronExpr: tt.cronExpr, TimeZone: tt.timeZone})
+			if err != nil {
+				t.Fatalf("parseSchedule() error = %v", err)
+			}
+
+			var got []time.Time
+			for next := utc(t, tt.from); len(got) < len(tt.want); {
+				next = schedule.Next(next)
+				got = append(got, next.UTC())
+			}
+			if want := utcs(t, tt.want...); !slices.EqualFunc(got, want, time.Time.Equal) {
+				t.Errorf("Next() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestParseScheduleInvalidTimeZone(t *testing.T) {
+	if _, err := parseSchedule(&domain.Job{CronExpr: "0 0 9 * * *", TimeZone: "Mars/Olympus_Mons"}); err == nil {
+		t.Fatal("parseSchedule() error = nil, want an invalid time zone error")
+	}
+}
+
+func TestMissedFireTimes(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeZone string
+		cronExpr string
+		after    string
+		now      string
+		limit    int
+		want     []string
+	}{
+		{
+			name:     "across the New York fall-back repeat",
+			timeZone: "America/New_York",
+			cronExpr: "0 30 1 * * *",
+			after:    "2024-11-02T06:00:00Z",
+			now:      "2024-11-05T00:00:00Z",
+			limit:    10,
+			want:     []string{"2024-11-03T05:30:00Z", "2024-11-04T06:30:00Z"},
+		},
+		{
+			name:     "limit keeps the latest runs",
+			timeZone: "America/New_York",
+			cronExpr: "0 30 1 * * *",
+			after:    "2024-11-02T06:00:00Z",
+			now:      "2024-11-05T00:00:00Z",
+			limit:    1,
+			want:     []string{"2024-11-04T06:30:00Z"},
+		},
+		{
+			name:     "across the Berlin spring-forward gap",
+			timeZone: "Europe/Berlin",
+			cronExpr: "0 30 2 * * *",
+			after:    "2024-03-30T00:00:00Z",
+			now:      "2024-04-02T00:00:00Z",
+			limit:    10,
+			want:     []string{"2024-03-30T01:30:00Z", "2024-04-01T00:30:00Z"},
+		},
+		{
+			name:     "a run due exactly now is missed",
+			timeZone: "Asia/Shanghai",
+			cronExpr: "0 0 9 * * *",
+			after:    "2024-03-09T01:00:00Z",
+			now:      "2024-03-10T01:00:00Z",
+			limit:    10,
+			want:     []string{"2024-03-10T01:00:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := parseSchedule(&domain.Job{CronExpr: tt.cronExpr, TimeZone: tt.timeZone})
+			if err != nil {
+				t.Fatalf("parseSchedule() error = %v", err)
+			}
+
+			got := missedFireTimes(schedule, utc(t, tt.after), utc(t, tt.now), tt.limit)
+			for i := range got {
+				got[i] = got[i].UTC()
+			}
+			if want := utcs(t, tt.want...); !slices.EqualFunc(got, want, time.Time.Equal) {
+				t.Errorf("missedFireTimes() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// fakeFireTimes is an in-memory domain.FireTimeRepository.
+type fakeFireTimes map[string]time.Time
+
+func (f fakeFireTimes) SaveLastFireTime(_ context.Context, jobName string, firedAt time.Time) error {
+	f[jobName] = firedAt
+	return nil
+}
+
+func (f fakeFireTimes) GetLastFireTime(_ context.Context, jobName string) (time.Time, error) {
+	return f[jobName], nil
+}
+
+func (f fakeFireTimes) DeleteLastFireTime(_ context.Context, jobName string) error {
+	delete(f, jobName)
+	return nil
+}
+
+func TestCollectMisfiresAcrossFallBack(t *testing.T) {
+	fireTimes := fakeFireTimes{"report": utc(t, "2024-11-02T05:30:00Z")}
+	s := NewCronScheduler(nil, fireTimes, slog.New(slog.DiscardHandler)).(*cronScheduler)
+	now := utc(t, "2024-11-04T12:00:00Z")
+	s.now = func() time.Time { return now }
+
+	job := &domain.Job{
+		Name:          "report",
+		CronExpr:      "0 30 1 * * *",
+		TimeZone:      "America/New_York",
+		MisfirePolicy: domain.MisfirePolicyFireAll,
+	}
+	if err := s.AddJob(job); err != nil {
+		t.Fatalf("AddJob() error = %v", err)
+	}
+
+	misfires := s.collectMisfires(context.Background())
+	if len(misfires) != 1 {
+		t.Fatalf("collectMisfires() returned %d jobs, want 1", len(misfires))
+	}
+	got := misfires[0].scheduledAt
+	for i := range got {
+		got[i] = got[i].UTC()
+	}
+	if want := utcs(t, "2024-11-03T05:30:00Z", "2024-11-04T06:30:00Z"); !slices.EqualFunc(got, want, time.Time.Equal) {
+		t.Errorf("collectMisfires() scheduled at %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+
+	"distributed-cron/internal/domain"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ClusterService reports on the workers and masters of the cluster.
+type ClusterService struct {
+	workers  domain.WorkerDirectory
+	observer domain.ClusterObserver
+	nodeID   string // The ID of this master
+	logger   *slog.Logger
+	tracer   trace.Tracer
+}
+
+// NewClusterService creates a new ClusterService instance.
+func NewClusterService(workers domain.WorkerDirectory, observer domain.ClusterObserver, nodeID string, logger *slog.Logger) *ClusterService {
+	return &ClusterService{
+		workers:  workers,
+		observer: observer,
+		nodeID:   nodeID,
+		logger:   logger,
+		tracer:   otel.Tracer("distributed-cron-usecase"),
+	}
+}
+
+// ListWorkers returns the registered workers with their load and health, ordered by ID.
+func (s *ClusterService) ListWorkers(ctx context.Context) ([]domain.WorkerStatus, error) {
+	ctx, span := s.tracer.Start(ctx, "service.ListWorkers")
+	defer span.End()
+
+	workers, err := s.workers.ListWorkers(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to list workers")
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("workers.count", len(workers)))
+	return workers, nil
+}
+
+// GetWorker returns a registered worker with its load and health.
+func (s *ClusterService) GetWorker(ctx context.Context, workerID string) (*domain.WorkerStatus, error) {
+	ctx, span := s.tracer.Start(ctx, "service.GetWorker")
+	defer span.End()
+	span.SetAttributes(attribute.String("worker.id", workerID))
+
+	worker, err := s.workers.GetWorker(ctx, workerID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to get worker")
+	}
+	return worker, err
+}
+
+// GetCluster returns the current leader and all masters campaigning for leadership.
+func (s *ClusterService) GetCluster(ctx context.Context) (*domain.ClusterStatus, error) {
+	ctx, span := s.tracer.Start(ctx, "service.GetCluster")
+	defer span.End()
+
+	candidates, err := s.observer.Candidates(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to list election candidates")
+		return nil, err
+	}
+
+	status := &domain.ClusterStatus{NodeID: s.nodeID, Candidates: candidates}
+	if len(candidates) > 0 {
+		status.Leader = candidates[0].NodeID
+	}
+	span.SetAttributes(
+		attribute.String("cluster.leader", status.Leader),
+		attribute.Int("cluster.candidates", len(candidates)),
+	)
+	return status, nil
+}
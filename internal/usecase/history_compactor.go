@@ -0,0 +1,232 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"distributed-cron/internal/domain"
+	"distributed-cron/internal/metrics"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultCompactionInterval  = 10 * time.Minute
+	defaultCompactionBatchSize = 100
+
+	// abandonedGrace is how long an execution may outlast its timeouts and retry delays,
+	// e.g. while a retry waits for a new leader, before its record counts as abandoned.
+	abandonedGrace = time.Hour
+)
+
+// HistoryCompactor deletes execution records that fall outside the retention policy of
+// their job, and the records of deleted jobs. It runs on the leader only, as a LeaderTask.
+type HistoryCompactor struct {
+	jobRepo   domain.JobRepository
+	execRepo  domain.ExecutionRepository
+	archive   domain.ExecutionArchive // Receives pruned records before they are deleted; may be nil
+	defaults  domain.RetentionPolicy
+	interval  time.Duration
+	batchSize int
+	logger    *slog.Logger
+	tracer    trace.Tracer
+}
+
+// NewHistoryCompactor creates a new HistoryCompactor. defaults applies to jobs that do not
+// set their own retention; the history is compacted every interval, batchSize records at a time.
+func NewHistoryCompactor(jobRepo domain.JobRepository, execRepo domain.ExecutionRepository, archive domain.ExecutionArchive, defaults domain.RetentionPolicy, interval time.Duration, batchSize int, logger *slog.Logger) *HistoryCompactor {
+	if interval <= 0 {
+		interval = defaultCompactionInterval
+	}
+	if batchSize <= 0 {
+		batchSize = defaultCompactionBatchSize
+	}
+	return &HistoryCompactor{
+		jobRepo:   jobRepo,
+		execRepo:  execRepo,
+		archive:   archive,
+		defaults:  defaults,
+		interval:  interval,
+		batchSize: batchSize,
+		logger:    logger.With("component", "history-compactor"),
+		tracer:    otel.Tracer("distributed-cron-usecase"),
+	}
+}
+
+// Run compacts the history right away and then every interval until ctx is cancelled.
+func (c *HistoryCompactor) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+	for {
+		c.compact(ctx)
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (c *HistoryCompactor) compact(ctx context.Context) {
+	ctx, span := c.tracer.Start(ctx, "service.CompactHistory")
+	defer span.End()
+
+	jobs, err := c.jobRepo.List(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to list jobs from repository")
+		c.logger.Error("failed to list jobs for history compaction", "error", err)
+		return
+	}
+
+	total := 0
+	known := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		known[job.Name] = true
+		pruned, err := c.compactJob(ctx, job)
+		total += pruned
+		if err != nil {
+			span.RecordError(err)
+			c.logger.Error("failed to compact execution history", "job_name", job.Name, "error", err)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if ctx.Err() == nil {
+		total += c.sweepOrphans(ctx, known)
+	}
+	span.SetAttributes(attribute.Int("records_pruned", total))
+	if total > 0 {
+		c.logger.Info("compacted execution history", "records_pruned", total)
+	}
+}
+
+// compactJob deletes the records of a job that its retention policy expires.
+func (c *HistoryCompactor) compactJob(ctx context.Context, job *domain.Job) (int, error) {
+	policy := job.Retention.Merge(c.defaults)
+	if policy.MaxCount == 0 && policy.MaxAge == 0 {
+		return 0, nil
+	}
+	var before time.Time
+	if policy.MaxAge > 0 {
+		before = time.Now().Add(-policy.MaxAge)
+	}
+	return c.prune(ctx, job.Name, policy.MaxCount, before, abandonedAfter(job))
+}
+
+// abandonedAfter returns how long after it started an execution of job is abandoned if it
+// is still running or waiting for a retry: the timeout of every attempt, the longest
+// delays between them and abandonedGrace.
+func abandonedAfter(job *domain.Job) time.Duration {
+	timeout := job.Timeout
+	if timeout == 0 {
+		timeout = domain.DefaultJobTimeout
+	}
+	total := timeout + abandonedGrace
+	if job.RetryPolicy != nil {
+		policy := *job.RetryPolicy
+		policy.Jitter = 0 // Jitter only shortens a delay
+		for retry := 1; retry <= policy.MaxRetries; retry++ {
+			total += policy.Delay(retry) + timeout
+		}
+	}
+	return total
+}
+
+// sweepOrphans deletes the history of jobs that no longer exist, e.g. because they were
+// deleted, and returns how many records were deleted. known holds the listed jobs.
+func (c *HistoryCompactor) sweepOrphans(ctx context.Context, known map[string]bool) int {
+	names, err := c.execRepo.ListJobNames(ctx)
+	if err != nil {
+		trace.SpanFromContext(ctx).RecordError(err)
+		c.logger.Error("failed to list jobs with execution history", "error", err)
+		return 0
+	}
+
+	total := 0
+	for _, name := range names {
+		if known[name] {
+			continue
+		}
+		// The job may have been created since the list was taken.
+		if _, err := c.jobRepo.Get(ctx, name); !errors.Is(err, domain.ErrJobNotFound) {
+			continue
+		}
+		// Without the job there is no timeout to wait for; a record that is still running
+		// after abandonedGrace is taken as abandoned.
+		pruned, err := c.prune(ctx, name, 0, time.Now(), abandonedGrace)
+		total += pruned
+		if err != nil {
+			trace.SpanFromContext(ctx).RecordError(err)
+			c.logger.Error("failed to delete execution history of deleted job", "job_name", name, "error", err)
+		} else if pruned > 0 {
+			c.logger.Info("deleted execution history of deleted job", "job_name", name, "records_pruned", pruned)
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return total
+}
+
+// prune deletes the records of a job that are not among its keep newest ones or that
+// did not start after before, batch by batch, and returns how many were deleted. Records
+// of executions that are still running or waiting for a retry are skipped, unless they
+// started more than abandonAfter ago: those executions are abandoned, e.g. because their
+// worker crashed, so their records are marked failed, archived and deleted as well.
+func (c *HistoryCompactor) prune(ctx context.Context, jobName string, keep int, before time.Time, abandonAfter time.Duration) (int, error) {
+	abandonedBefore := time.Now().Add(-abandonAfter)
+	pruned := 0
+	cursor := ""
+	for ctx.Err() == nil {
+		records, next, err := c.execRepo.ListExpired(ctx, jobName, keep, before, cursor, c.batchSize)
+		if err != nil {
+			return pruned, err
+		}
+
+		expired := make([]*domain.ExecutionRecord, 0, len(records))
+		for _, record := range records {
+			if record.Status == domain.ExecutionStatusRunning || record.Status == domain.ExecutionStatusRetryPending {
+				if !record.StartTime.Before(abandonedBefore) {
+					continue
+				}
+				c.logger.Warn("pruning abandoned execution", "job_name", jobName, "execution_id", record.ID, "status", record.Status, "start_time", record.StartTime)
+				record.Error = fmt.Sprintf("abandoned while %s", record.Status)
+				record.Status = domain.ExecutionStatusFailed
+			}
+			expired = append(expired, record)
+		}
+
+		if len(expired) > 0 {
+			if c.archive != nil {
+				if err := c.archive.Archive(ctx, expired); err != nil {
+					return pruned, fmt.Errorf("failed to archive execution records: %w", err)
+				}
+			}
+			ids := make([]string, len(expired))
+			for i, record := range expired {
+				ids[i] = record.ID
+			}
+			deleted, err := c.execRepo.Delete(ctx, jobName, ids)
+			pruned += deleted
+			metrics.ExecutionRecordsPrunedTotal.WithLabelValues(jobName).Add(float64(deleted))
+			if err != nil {
+				return pruned, err
+			}
+		}
+
+		// The cursor moves past the skipped records too, so they do not hold up the rest.
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+	return pruned, nil
+}
@@ -2,6 +2,9 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"time"
 
@@ -14,44 +17,240 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+// ErrOverrideNotSupported is returned when a trigger override does not apply to the job's executor type.
+var ErrOverrideNotSupported = errors.New("override not supported for this executor type")
+
+// TriggerOverrides holds one-off changes applied to a single manually triggered run.
+// The stored job definition is never modified.
+type TriggerOverrides struct {
+	Command string            // Replaces the shell command for this run
+	Env     map[string]string // Merged over the shell job's own environment for this run
+}
+
 // jobService 实现了对 Job 的核心业务逻辑操作。
+// Job writes only go to the repository; the leader watches the repository and
+// updates its scheduler, so any master can serve them.
 type JobService struct {
-	repo      domain.JobRepository
-	execRepo  domain.ExecutionRepository // Add dependency for execution records
-	scheduler domain.Schedular
-	logger    *slog.Logger
-	tracer    trace.Tracer
+	repo       domain.JobRepository
+	execRepo   domain.ExecutionRepository // Add dependency for execution records
+	dispatcher domain.Dispatcher
+	controller domain.ExecutionController
+	leader     domain.LeaderElectionManager // Manual triggers are dispatched by the leader only
+	logger     *slog.Logger
+	tracer     trace.Tracer
 }
 
 // NewJobService creates a new JobService instance.
-func NewJobService(repo domain.JobRepository, execRepo domain.ExecutionRepository, scheduler domain.Schedular, logger *slog.Logger) *JobService {
+func NewJobService(repo domain.JobRepository, execRepo domain.ExecutionRepository, dispatcher domain.Dispatcher, controller domain.ExecutionController, leader domain.LeaderElectionManager, logger *slog.Logger) *JobService {
 	return &JobService{
-		repo:      repo,
-		execRepo:  execRepo,
-		scheduler: scheduler,
-		logger:    logger,
-		tracer:    otel.Tracer("distributed-cron-usecase"),
+		repo:       repo,
+		execRepo:   execRepo,
+		dispatcher: dispatcher,
+		controller: controller,
+		leader:     leader,
+		logger:     logger,
+		tracer:     otel.Tracer("distributed-cron-usecase"),
 	}
 }
 
 // ... (Save, Delete, Get, List methods remain the same) ...
 
-// ListHistory lists the execution history for a specific job.
-func (s *JobService) ListHistory(ctx context.Context, jobName string, page, pageSize int) ([]*domain.ExecutionRecord, error) {
+// ListHistory lists a page of the execution history of a job, newest first, and
+// returns the cursor of the next page ("" on the last page).
+func (s *JobService) ListHistory(ctx context.Context, jobName, cursor string, pageSize int) ([]*domain.ExecutionRecord, string, error) {
 	ctx, span := s.tracer.Start(ctx, "service.ListHistory")
 	defer span.End()
 	span.SetAttributes(
 		attribute.String("job.name", jobName),
-		attribute.Int("page", page),
+		attribute.String("cursor", cursor),
 		attribute.Int("page_size", pageSize),
 	)
 
-	records, err := s.execRepo.ListByJobName(ctx, jobName, page, pageSize)
+	records, next, err := s.execRepo.ListByJobName(ctx, jobName, cursor, pageSize)
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "failed to list job history from repository")
 	}
-	return records, err
+	return records, next, err
+}
+
+// QueryExecutions lists a page of the execution records of all jobs that match q, newest
+// first, and returns the cursor of the next page ("" on the last page).
+func (s *JobService) QueryExecutions(ctx context.Context, q domain.ExecutionQuery) ([]*domain.ExecutionRecord, string, error) {
+	ctx, span := s.tracer.Start(ctx, "service.QueryExecutions")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("job.name", q.JobName),
+		attribute.String("status", string(q.Status)),
+		attribute.String("worker.id", q.WorkerID),
+		attribute.String("cursor", q.Cursor),
+		attribute.Int("limit", q.Limit),
+	)
+
+	records, next, err := s.execRepo.Query(ctx, q)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to query executions from repository")
+	}
+	return records, next, err
+}
+
+// GetExecution retrieves the full record of a single execution.
+func (s *JobService) GetExecution(ctx context.Context, jobName, executionID string) (*domain.ExecutionRecord, error) {
+	ctx, span := s.tracer.Start(ctx, "service.GetExecution")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", jobName), attribute.String("execution.id", executionID))
+
+	record, err := s.getExecution(ctx, jobName, executionID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to get execution record from repository")
+	}
+	return record, err
+}
+
+// getExecution returns the record of an execution of the job jobName. A record of another
+// job is reported as not found, so an execution cannot be reached under a job it does not
+// belong to.
+func (s *JobService) getExecution(ctx context.Context, jobName, executionID string) (*domain.ExecutionRecord, error) {
+	record, err := s.execRepo.Get(ctx, jobName, executionID)
+	if err != nil {
+		return nil, err
+	}
+	if record.JobName != jobName {
+		return nil, fmt.Errorf("%w: %s/%s", domain.ErrExecutionNotFound, jobName, executionID)
+	}
+	return record, nil
+}
+
+// Trigger dispatches a job immediately, outside of its schedule, and returns the execution ID.
+// Only the leader dispatches, as it does for scheduled runs, so a follower returns ErrNotLeader.
+func (s *JobService) Trigger(ctx context.Context, name string, overrides TriggerOverrides) (string, error) {
+	ctx, span := s.tracer.Start(ctx, "service.Trigger")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", name))
+
+	if !s.leader.IsLeader() {
+		span.SetStatus(codes.Error, "not the leader")
+		return "", domain.ErrNotLeader
+	}
+
+	job, err := s.repo.Get(ctx, name)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to get job from repository")
+		return "", err
+	}
+
+	if overrides.Command != "" || len(overrides.Env) > 0 {
+		if job.ExecutorType != domain.ExecutorTypeShell {
+			return "", fmt.Errorf("%w: command and env overrides require a shell job", ErrOverrideNotSupported)
+		}
+		env := make(map[string]string, len(job.Executor.Env)+len(overrides.Env))
+		for k, v := range job.Executor.Env {
+			env[k] = v
+		}
+		for k, v := range overrides.Env {
+			env[k] = v
+		}
+		job.Executor.Env = env
+		if overrides.Command != "" {
+			job.Executor.Command = overrides.Command
+		}
+		span.AddEvent("overrides_applied")
+	}
+
+	executionID, err := s.dispatcher.DispatchTask(ctx, job, domain.DispatchOptions{Trigger: domain.TriggerManual})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to dispatch job")
+		return "", err
+	}
+	span.SetAttributes(attribute.String("execution.id", executionID))
+	s.logger.Info("job triggered manually", "job_name", name, "execution_id", executionID)
+	return executionID, nil
+}
+
+// CancelExecution stops a running execution on the worker recorded in its execution record.
+func (s *JobService) CancelExecution(ctx context.Context, jobName, executionID string) error {
+	ctx, span := s.tracer.Start(ctx, "service.CancelExecution")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", jobName), attribute.String("execution.id", executionID))
+
+	record, err := s.getExecution(ctx, jobName, executionID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to get execution record from repository")
+		return err
+	}
+	if record.Status != domain.ExecutionStatusRunning {
+		return fmt.Errorf("%w: status is %s", domain.ErrExecutionNotRunning, record.Status)
+	}
+	span.SetAttributes(attribute.String("worker.id", record.WorkerID))
+
+	if err := s.controller.CancelExecution(ctx, record.WorkerID, executionID); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to cancel execution on worker")
+		return err
+	}
+	return nil
+}
+
+// StreamExecutionLogs writes the output of an execution to w. Running executions are
+// relayed from their worker, and with follow set the output is streamed until the
+// execution ends. Finished executions are served from their stored record.
+func (s *JobService) StreamExecutionLogs(ctx context.Context, jobName, executionID string, follow bool, w io.Writer) error {
+	ctx, span := s.tracer.Start(ctx, "service.StreamExecutionLogs")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("job.name", jobName),
+		attribute.String("execution.id", executionID),
+		attribute.Bool("follow", follow),
+	)
+
+	record, err := s.getExecution(ctx, jobName, executionID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to get execution record from repository")
+		return err
+	}
+
+	if record.Status == domain.ExecutionStatusRunning {
+		err = s.controller.StreamExecutionLogs(ctx, record.WorkerID, executionID, follow, w)
+		if !errors.Is(err, domain.ErrExecutionNotRunning) {
+			return err
+		}
+		// The execution finished before the stream was opened; fall back to its final record.
+		if record, err = s.getExecution(ctx, jobName, executionID); err != nil {
+			return err
+		}
+	}
+
+	_, err = io.WriteString(w, record.Output)
+	return err
+}
+
+// GetExecutionOutput writes the full output of an execution to w. If the worker that ran
+// it is gone or no longer has the output, the truncated output of the record is written instead.
+func (s *JobService) GetExecutionOutput(ctx context.Context, jobName, executionID string, w io.Writer) error {
+	ctx, span := s.tracer.Start(ctx, "service.GetExecutionOutput")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", jobName), attribute.String("execution.id", executionID))
+
+	record, err := s.getExecution(ctx, jobName, executionID)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to get execution record from repository")
+		return err
+	}
+
+	err = s.controller.GetExecutionOutput(ctx, record.WorkerID, jobName, executionID, w)
+	if !errors.Is(err, domain.ErrLogNotFound) && !errors.Is(err, domain.ErrWorkerNotFound) {
+		return err
+	}
+	span.AddEvent("full_output_not_found", trace.WithAttributes(attribute.String("reason", err.Error())))
+	_, err = io.WriteString(w, record.Output)
+	return err
 }
 
 // Save 处理保存一个任务的业务逻辑。
@@ -63,6 +262,18 @@ func (s *JobService) Save(ctx context.Context, job *domain.Job) error {
 		return err
 	}
 
+	// Updating an existing job keeps its identity and its paused state.
+	existing, err := s.repo.Get(ctx, job.Name)
+	if err == nil {
+		job.ID = existing.ID
+		job.CreatedAt = existing.CreatedAt
+		job.Paused = existing.Paused
+	} else if !errors.Is(err, domain.ErrJobNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to get job from repository")
+		return err
+	}
+
 	now := time.Now()
 	if job.ID == "" {
 		job.ID = uuid.New().String()
@@ -77,13 +288,44 @@ func (s *JobService) Save(ctx context.Context, job *domain.Job) error {
 		return err
 	}
 
-	if err := s.scheduler.AddJob(job); err != nil {
+	return nil
+}
+
+// Pause stops scheduling a job while keeping its definition.
+func (s *JobService) Pause(ctx context.Context, name string) (*domain.Job, error) {
+	return s.setPaused(ctx, name, true)
+}
+
+// Resume puts a paused job back on its schedule.
+func (s *JobService) Resume(ctx context.Context, name string) (*domain.Job, error) {
+	return s.setPaused(ctx, name, false)
+}
+
+func (s *JobService) setPaused(ctx context.Context, name string, paused bool) (*domain.Job, error) {
+	ctx, span := s.tracer.Start(ctx, "service.SetPaused")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", name), attribute.Bool("job.paused", paused))
+
+	job, err := s.repo.Get(ctx, name)
+	if err != nil {
 		span.RecordError(err)
-		span.SetStatus(codes.Error, "failed to add job to scheduler")
-		return err
+		span.SetStatus(codes.Error, "failed to get job from repository")
+		return nil, err
+	}
+	if job.Paused == paused {
+		return job, nil
 	}
 
-	return nil
+	job.Paused = paused
+	job.UpdatedAt = time.Now()
+	if err := s.repo.Save(ctx, job); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "failed to save job to repository")
+		return nil, err
+	}
+
+	s.logger.Info("job pause state changed", "job_name", name, "paused", paused)
+	return job, nil
 }
 
 // Delete 处理删除一个任务的业务逻辑。
@@ -92,12 +334,6 @@ func (s *JobService) Delete(ctx context.Context, name string) error {
 	defer span.End()
 	span.SetAttributes(attribute.String("job.name", name))
 
-	if err := s.scheduler.RemoveJob(name); err != nil {
-		span.RecordError(err)
-		span.SetStatus(codes.Error, "failed to remove job from scheduler")
-		return err
-	}
-
 	if err := s.repo.Delete(ctx, name); err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "failed to delete job from repository")
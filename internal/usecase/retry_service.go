@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"distributed-cron/internal/domain"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RetryService dispatches the retries that workers hand back to the master to a
+// different worker. It runs on the leader only, as a LeaderTask.
+type RetryService struct {
+	queue      domain.RetryQueue
+	jobRepo    domain.JobRepository
+	execRepo   domain.ExecutionRepository
+	dispatcher domain.Dispatcher
+	logger     *slog.Logger
+	tracer     trace.Tracer
+}
+
+// NewRetryService creates a new RetryService instance.
+func NewRetryService(queue domain.RetryQueue, jobRepo domain.JobRepository, execRepo domain.ExecutionRepository, dispatcher domain.Dispatcher, logger *slog.Logger) *RetryService {
+	return &RetryService{
+		queue:      queue,
+		jobRepo:    jobRepo,
+		execRepo:   execRepo,
+		dispatcher: dispatcher,
+		logger:     logger.With("component", "retry-service"),
+		tracer:     otel.Tracer("distributed-cron-usecase"),
+	}
+}
+
+// Run handles retry requests until ctx is cancelled. Each request waits for its own
+// backoff, so a long delay does not hold up the others.
+func (s *RetryService) Run(ctx context.Context) {
+	for req := range s.queue.Watch(ctx) {
+		go s.handle(ctx, req)
+	}
+}
+
+func (s *RetryService) handle(ctx context.Context, req *domain.RetryRequest) {
+	if wait := time.Until(req.NotBefore); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return // The next leader picks the request up again.
+		}
+	}
+
+	ctx, span := s.tracer.Start(ctx, "service.DispatchRetry")
+	defer span.End()
+	span.SetAttributes(
+		attribute.String("job.name", req.JobName),
+		attribute.String("execution.id", req.ExecutionID),
+		attribute.Int("retry.attempt", req.Attempt),
+	)
+
+	// Claim the request first, so that it is dispatched at most once.
+	claimed, err := s.queue.Remove(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		s.logger.Error("failed to claim retry request", "execution_id", req.ExecutionID, "error", err)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	job, err := s.jobRepo.Get(ctx, req.JobName)
+	if err != nil {
+		s.failExecution(ctx, span, req, err)
+		return
+	}
+
+	_, err = s.dispatcher.DispatchTask(ctx, job, domain.DispatchOptions{
+		Trigger:          domain.TriggerRetry,
+		ExecutionID:      req.ExecutionID,
+		Attempt:          req.Attempt,
+		ExcludeWorkerIDs: req.ExcludeWorkerIDs,
+	})
+	if err != nil {
+		s.failExecution(ctx, span, req, err)
+		return
+	}
+	s.logger.Info("retry dispatched", "job_name", req.JobName, "execution_id", req.ExecutionID, "attempt", req.Attempt)
+}
+
+// failExecution closes the record of an execution whose retry could not be dispatched.
+func (s *RetryService) failExecution(ctx context.Context, span trace.Span, req *domain.RetryRequest, cause error) {
+	span.RecordError(cause)
+	span.SetStatus(codes.Error, "failed to dispatch retry")
+	s.logger.Error("failed to dispatch retry", "job_name", req.JobName, "execution_id", req.ExecutionID, "attempt", req.Attempt, "error", cause)
+
+	record, err := s.execRepo.Get(ctx, req.JobName, req.ExecutionID)
+	if err != nil {
+		s.logger.Error("failed to load execution record of retry", "execution_id", req.ExecutionID, "error", err)
+		return
+	}
+	record.Status = domain.ExecutionStatusFailed
+	record.Error = fmt.Sprintf("%s; retry attempt %d could not be dispatched: %v", record.Error, req.Attempt, cause)
+	record.EndTime = time.Now()
+	if err := s.execRepo.Save(ctx, record); err != nil {
+		s.logger.Error("failed to save execution record of retry", "execution_id", req.ExecutionID, "error", err)
+	}
+}
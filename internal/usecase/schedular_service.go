@@ -7,19 +7,29 @@ import (
 	"time"
 )
 
+// LeaderTask is background work that only the leader performs. Run is started at the
+// beginning of every leadership term and must return once ctx is cancelled.
+type LeaderTask interface {
+	Run(ctx context.Context)
+}
+
 type SchedularService struct {
 	leaderManager domain.LeaderElectionManager
 	schedular     domain.Schedular
 	jobRepo       domain.JobRepository
+	jobWatcher    domain.JobWatcher
 	nodeID        string
+	leaderTasks   []LeaderTask
 }
 
-func NewSchedularService(leaderManager domain.LeaderElectionManager, schedular domain.Schedular, jobRepo domain.JobRepository, nodeID string) *SchedularService {
+func NewSchedularService(leaderManager domain.LeaderElectionManager, schedular domain.Schedular, jobRepo domain.JobRepository, jobWatcher domain.JobWatcher, nodeID string, leaderTasks ...LeaderTask) *SchedularService {
 	return &SchedularService{
 		leaderManager: leaderManager,
 		schedular:     schedular,
 		jobRepo:       jobRepo,
+		jobWatcher:    jobWatcher,
 		nodeID:        nodeID,
+		leaderTasks:   leaderTasks,
 	}
 }
 
@@ -42,13 +52,22 @@ func (s *SchedularService) Start(ctx context.Context) error {
 			}
 
 			log.Printf("Node %s successfully became the leader. Starting the scheduler.", s.nodeID)
-			s.runSchedular(ctx)
+			// The scheduler only runs for the duration of this leadership term, so a node
+			// that loses leadership stops firing before the next leader catches up.
+			termCtx, termCancel := context.WithCancel(ctx)
+			s.runSchedular(termCtx)
 
 			select {
 			case <-lostLeaderShipCh:
+				log.Printf("Node %s lost leadership. Stopping the scheduler.", s.nodeID)
+				termCancel()
 				s.schedular.Stop()
+				// Nothing of this term may fire in a later one; the next term lists the jobs again.
+				s.schedular.Clear()
 			case <-ctx.Done():
+				termCancel()
 				s.schedular.Stop()
+				s.schedular.Clear()
 				return ctx.Err()
 			}
 		}
@@ -56,21 +75,54 @@ func (s *SchedularService) Start(ctx context.Context) error {
 }
 
 func (s *SchedularService) runSchedular(ctx context.Context) {
-
-	jobs, err := s.jobRepo.List(ctx)
-	if err != nil {
-		log.Printf("Node %s error loading jobs for scheduler: %v", s.nodeID, err)
+	// The first event lists every job. Later events are the changes made after it, or
+	// fresh lists whenever the watch had to be re-established.
+	events := s.jobWatcher.Watch(ctx)
+	first, ok := <-events
+	if !ok {
+		log.Printf("Node %s error loading jobs for scheduler: job watch closed", s.nodeID)
 		return
 	}
-
-	for _, job := range jobs {
-		if err = s.schedular.AddJob(job); err != nil {
-			return
-		}
-	}
+	s.applyJobEvent(first)
 
 	go func() {
 		if err := s.schedular.Start(ctx); err != nil {
 		}
 	}()
+
+	go s.reconcileJobs(ctx, events)
+
+	for _, task := range s.leaderTasks {
+		go task.Run(ctx)
+	}
+}
+
+// reconcileJobs applies job changes made through any master to the leader's scheduler.
+func (s *SchedularService) reconcileJobs(ctx context.Context, events <-chan domain.JobEvent) {
+	for event := range events {
+		s.applyJobEvent(event)
+	}
+	log.Printf("Node %s stopped watching job changes.", s.nodeID)
+}
+
+func (s *SchedularService) applyJobEvent(event domain.JobEvent) {
+	switch event.Type {
+	case domain.JobEventSync:
+		// Start from an empty scheduler, so jobs deleted since the last list are dropped.
+		// AddJob leaves paused jobs unscheduled.
+		s.schedular.Clear()
+		for _, job := range event.Jobs {
+			if err := s.schedular.AddJob(job); err != nil {
+				log.Printf("Node %s failed to schedule job %s: %v", s.nodeID, job.Name, err)
+			}
+		}
+	case domain.JobEventPut:
+		if err := s.schedular.AddJob(event.Job); err != nil {
+			log.Printf("Node %s failed to reschedule job %s: %v", s.nodeID, event.Name, err)
+		}
+	case domain.JobEventDelete:
+		if err := s.schedular.RemoveJob(event.Name); err != nil {
+			log.Printf("Node %s failed to unschedule job %s: %v", s.nodeID, event.Name, err)
+		}
+	}
 }
@@ -0,0 +1,30 @@
+// internal/worker/log_pruner.go
+package worker
+
+import (
+	"context"
+	"time"
+)
+
+// logPruneInterval is how often a worker looks for expired execution output.
+const logPruneInterval = time.Hour
+
+// PruneLogs deletes the execution output that was last written more than maxAge ago,
+// right away and then every logPruneInterval until ctx is cancelled.
+func (s *Server) PruneLogs(ctx context.Context, maxAge time.Duration) {
+	ticker := time.NewTicker(logPruneInterval)
+	defer ticker.Stop()
+	for {
+		pruned, err := s.logStore.Prune(ctx, time.Now().Add(-maxAge))
+		if err != nil {
+			s.logger.Error("failed to prune execution logs", "error", err)
+		} else if pruned > 0 {
+			s.logger.Info("pruned execution logs", "logs_pruned", pruned)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// internal/worker/log_stream.go
+package worker
+
+import (
+	"fmt"
+	"io"
+	"sync"
+)
+
+// liveWindow is how much of the most recent output is kept in memory for followers.
+const liveWindow = 1 << 20
+
+// logStream collects the output of a single execution and lets any number of
+// followers read it while it is still being written. Memory use is bounded: followers
+// only see the last liveWindow bytes, the record only keeps a head and a tail of the
+// output, and the full output goes to the log store.
+type logStream struct {
+	mu      sync.Mutex
+	data    []byte // The most recent output, starting at offset base
+	base    int
+	capture *outputCapture
+	store   io.WriteCloser // Receives the full output; nil if the log store is not available
+	closed  bool
+	changed chan struct{} // closed and replaced on every write and on Close
+}
+
+func newLogStream(limit int, store io.WriteCloser) *logStream {
+	return &logStream{
+		capture: newOutputCapture(limit),
+		store:   store,
+		changed: make(chan struct{}),
+	}
+}
+
+// Write appends p to the stream and wakes up all followers.
+func (l *logStream) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.store != nil {
+		if _, err := l.store.Write(p); err != nil {
+			// Keep running the job; only the full output is incomplete.
+			l.store.Close()
+			l.store = nil
+		}
+	}
+	l.capture.Write(p)
+
+	l.data = append(l.data, p...)
+	if len(l.data) > 2*liveWindow {
+		drop := len(l.data) - liveWindow
+		l.data = append([]byte(nil), l.data[drop:]...)
+		l.base += drop
+	}
+	l.notify()
+	return len(p), nil
+}
+
+// Close marks the stream as complete. Followers drain the remaining data and stop.
+func (l *logStream) Close() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.store != nil {
+		l.store.Close()
+		l.store = nil
+	}
+	l.closed = true
+	l.notify()
+}
+
+// String returns the output to keep in the execution record, truncated to the stream's limit.
+func (l *logStream) String() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.capture.String()
+}
+
+// readFrom returns a copy of the data written after offset, the offset to read from
+// next, whether the stream is closed, and a channel that is closed on the next write or
+// on Close. Data that has left the live window is skipped with a marker.
+func (l *logStream) readFrom(offset int) ([]byte, int, bool, <-chan struct{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var chunk []byte
+	if offset < l.base {
+		chunk = fmt.Appendf(chunk, "\n... [%d bytes skipped] ...\n", l.base-offset)
+		offset = l.base
+	}
+	end := l.base + len(l.data)
+	if offset < end {
+		chunk = append(chunk, l.data[offset-l.base:]...)
+	}
+	return chunk, end, l.closed, l.changed
+}
+
+// notify must be called with l.mu held.
+func (l *logStream) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// outputCapture keeps the first and the last limit/2 bytes written to it.
+type outputCapture struct {
+	half  int
+	head  []byte
+	tail  []byte
+	total int64
+}
+
+func newOutputCapture(limit int) *outputCapture {
+	return &outputCapture{half: limit / 2}
+}
+
+func (c *outputCapture) Write(p []byte) {
+	c.total += int64(len(p))
+	if n := min(c.half-len(c.head), len(p)); n > 0 {
+		c.head = append(c.head, p[:n]...)
+		p = p[n:]
+	}
+	c.tail = append(c.tail, p...)
+	if len(c.tail) > 2*c.half {
+		c.tail = append([]byte(nil), c.tail[len(c.tail)-c.half:]...)
+	}
+}
+
+// String returns the captured output, with a marker where output was left out.
+func (c *outputCapture) String() string {
+	tail := c.tail
+	if len(tail) > c.half {
+		tail = tail[len(tail)-c.half:]
+	}
+	skipped := c.total - int64(len(c.head)) - int64(len(tail))
+	if skipped == 0 {
+		return string(c.head) + string(tail)
+	}
+	return fmt.Sprintf("%s\n... [%d bytes truncated, see the execution's full output] ...\n%s", c.head, skipped, tail)
+}
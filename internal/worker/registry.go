@@ -3,8 +3,12 @@ package worker
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"time"
+
+	"distributed-cron/internal/domain"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
 )
@@ -20,7 +24,8 @@ type Registry struct {
 	logger  *slog.Logger
 	leaseID clientv3.LeaseID
 	key     string
-	value   string
+
+	registration domain.WorkerRegistration // Last published registration; only used by Register and Heartbeat
 }
 
 // NewRegistry creates a new worker registry.
@@ -31,11 +36,12 @@ func NewRegistry(client *clientv3.Client, logger *slog.Logger) *Registry {
 	}
 }
 
-// Register registers the worker with etcd, providing its address.
+// Register registers the worker with etcd, publishing its address and capacity.
 // It starts a keep-alive goroutine for the lease.
-func (r *Registry) Register(ctx context.Context, workerID, workerAddr string, ttl int64) error {
+func (r *Registry) Register(ctx context.Context, workerID string, registration domain.WorkerRegistration, ttl int64) error {
 	r.key = WorkerRegistryPrefix + workerID
-	r.value = workerAddr
+	r.registration = registration
+	r.registration.RegisteredAt = time.Now()
 
 	// 1. Create a new lease with a TTL.
 	leaseResp, err := r.client.Grant(ctx, ttl)
@@ -45,9 +51,8 @@ func (r *Registry) Register(ctx context.Context, workerID, workerAddr string, tt
 	r.leaseID = leaseResp.ID
 
 	// 2. Put the worker's key-value pair into etcd with the lease.
-	_, err = r.client.Put(ctx, r.key, r.value, clientv3.WithLease(r.leaseID))
-	if err != nil {
-		return fmt.Errorf("failed to put worker registration key: %w", err)
+	if err := r.publish(ctx); err != nil {
+		return err
 	}
 
 	// 3. Start a keep-alive goroutine to periodically refresh the lease.
@@ -69,14 +74,49 @@ func (r *Registry) Register(ctx context.Context, workerID, workerAddr string, tt
 		}
 	}()
 
-	r.logger.Info("worker registered successfully", "key", r.key, "value", r.value)
+	r.logger.Info("worker registered successfully", "key", r.key, "addr", r.registration.Addr, "capacity", r.registration.Capacity, "labels", r.registration.Labels)
 	return nil
 }
 
+func (r *Registry) publish(ctx context.Context) error {
+	value, err := json.Marshal(r.registration)
+	if err != nil {
+		return fmt.Errorf("failed to marshal worker registration: %w", err)
+	}
+	if _, err := r.client.Put(ctx, r.key, string(value), clientv3.WithLease(r.leaseID)); err != nil {
+		return fmt.Errorf("failed to put worker registration key: %w", err)
+	}
+	return nil
+}
+
+// Heartbeat publishes the number of running executions, as reported by running, every
+// interval in which it changed, until ctx is cancelled. Must be called after Register.
+func (r *Registry) Heartbeat(ctx context.Context, interval time.Duration, running func() int) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		n := running()
+		if n == r.registration.Running {
+			continue
+		}
+		r.registration.Running = n
+		if err := r.publish(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Warn("failed to publish worker load", "running", n, "error", err)
+			r.registration.Running = -1 // Publish again on the next tick
+		}
+	}
+}
+
 // Deregister removes the worker's registration from etcd.
 func (r *Registry) Deregister(ctx context.Context) error {
 	r.logger.Info("deregistering worker", "key", r.key)
-	
+
 	// Revoke the lease, which will automatically delete the associated key.
 	if _, err := r.client.Revoke(ctx, r.leaseID); err != nil {
 		return fmt.Errorf("failed to revoke lease: %w", err)
@@ -0,0 +1,124 @@
+// internal/worker/retry.go
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"distributed-cron/internal/domain"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// runAttempts runs the job until an attempt succeeds or its retry policy is exhausted.
+// Retries run on this worker after the policy's backoff, unless the policy asks for a
+// different worker; then the record is saved as retry_pending, the next attempt is handed
+// to the master and retryPending is true.
+func (s *Server) runAttempts(ctx context.Context, job *domain.Job, executor domain.TaskExecutor, record *domain.ExecutionRecord, firstAttempt int, logs *logStream, logger *slog.Logger) (retryPending bool, err error) {
+	policy := job.RetryPolicy
+	maxAttempts := 1
+	if policy != nil {
+		maxAttempts += policy.MaxRetries
+	}
+
+	for attempt := firstAttempt; ; attempt++ {
+		err = s.runAttempt(ctx, job, executor, record, attempt, logs)
+		if err == nil {
+			return false, nil
+		}
+		if attempt >= maxAttempts || errors.Is(err, domain.ErrExecutionCancelled) || errors.Is(err, domain.ErrNonRetriable) {
+			return false, err
+		}
+
+		delay := policy.Delay(attempt)
+		fmt.Fprintf(logs, "\n[attempt %d/%d failed: %v; retrying in %s]\n", attempt, maxAttempts, err, delay)
+
+		if policy.RetryOnDifferentWorker && s.retryQueue != nil {
+			// Save the hand-off before enqueueing it: once enqueued, the next attempt may
+			// start at any time and must find this attempt in the record.
+			record.Status = domain.ExecutionStatusRetryPending
+			record.Error = err.Error()
+			record.Output = logs.String()
+			record.EndTime = time.Now()
+			if serr := s.execRepo.Save(ctx, record); serr != nil {
+				logger.Error("failed to save execution record before handing retry to the master", "attempt", attempt+1, "error", serr)
+				return false, err
+			}
+			if qerr := s.enqueueRetry(ctx, record, attempt+1, delay); qerr != nil {
+				logger.Error("failed to hand retry to the master", "attempt", attempt+1, "error", qerr)
+				return false, err
+			}
+			logger.Info("attempt failed, retry handed to the master", "attempt", attempt, "delay", delay, "error", err)
+			return true, err
+		}
+
+		logger.Warn("attempt failed, retrying", "attempt", attempt, "delay", delay, "error", err)
+		record.Status = domain.ExecutionStatusRunning
+		record.Output = logs.String()
+		if serr := s.execRepo.Save(ctx, record); serr != nil {
+			logger.Error("failed to save execution record between attempts", "error", serr)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return false, fmt.Errorf("%w: %w", context.Cause(ctx), err)
+		}
+	}
+}
+
+// runAttempt runs the job once within its timeout and appends the attempt to the record.
+func (s *Server) runAttempt(ctx context.Context, job *domain.Job, executor domain.TaskExecutor, record *domain.ExecutionRecord, attempt int, logs *logStream) error {
+	timeout := job.Timeout
+	if timeout <= 0 {
+		timeout = domain.DefaultJobTimeout
+	}
+	runCtx, cancel := context.WithTimeoutCause(ctx, timeout, domain.ErrExecutionTimedOut)
+	defer cancel()
+
+	attemptRecord := domain.ExecutionAttempt{Attempt: attempt, WorkerID: s.workerID, StartTime: time.Now()}
+	result, err := executor.Execute(runCtx, job, logs)
+	if err != nil && runCtx.Err() != nil {
+		// Surface why the execution was interrupted: cancelled by a user or timed out.
+		err = fmt.Errorf("%w: %w", context.Cause(runCtx), err)
+	}
+	attemptRecord.EndTime = time.Now()
+	record.ApplyResult(result, attemptRecord.EndTime.Sub(attemptRecord.StartTime))
+	if err != nil {
+		attemptRecord.Error = err.Error()
+	}
+
+	record.Attempts = append(record.Attempts, attemptRecord)
+	record.RetriesAttempted = len(record.Attempts) - 1
+	trace.SpanFromContext(ctx).AddEvent("attempt_finished", trace.WithAttributes(
+		attribute.Int("execution.attempt", attempt),
+		attribute.Bool("execution.failed", err != nil),
+	))
+	return err
+}
+
+// enqueueRetry asks the master to dispatch the next attempt to a worker that has not tried it yet.
+func (s *Server) enqueueRetry(ctx context.Context, record *domain.ExecutionRecord, attempt int, delay time.Duration) error {
+	seen := make(map[string]bool)
+	var exclude []string
+	for _, a := range record.Attempts {
+		if a.WorkerID != "" && !seen[a.WorkerID] {
+			seen[a.WorkerID] = true
+			exclude = append(exclude, a.WorkerID)
+		}
+	}
+
+	return s.retryQueue.Enqueue(ctx, &domain.RetryRequest{
+		JobName:          record.JobName,
+		ExecutionID:      record.ID,
+		Attempt:          attempt,
+		ExcludeWorkerIDs: exclude,
+		NotBefore:        time.Now().Add(delay),
+	})
+}
@@ -3,8 +3,11 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"sync"
 	"time"
 
 	"distributed-cron/internal/domain"
@@ -16,28 +19,50 @@ import (
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
+	grpccodes "google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // Server implements the proto.WorkerServer interface.
 type Server struct {
 	pb.UnimplementedWorkerServer
-	executors map[domain.ExecutorType]domain.TaskExecutor
-	locker    domain.Locker
-	execRepo  domain.ExecutionRepository
-	workerID  string // Add workerID to the server struct
-	logger    *slog.Logger
-	tracer    trace.Tracer
+	executors   map[domain.ExecutorType]domain.TaskExecutor
+	locker      domain.Locker
+	execRepo    domain.ExecutionRepository
+	retryQueue  domain.RetryQueue // Receives retries that must run on a different worker
+	logStore    domain.LogStore   // Keeps the full output of executions
+	workerID    string            // Add workerID to the server struct
+	outputLimit int               // Bytes of output kept in an execution record
+	logger      *slog.Logger
+	tracer      trace.Tracer
+
+	running   map[string]*runningExecution // executionID -> execution in progress on this worker
+	runningMu sync.Mutex
+}
+
+// runningExecution tracks an execution in progress so that it can be controlled by the master.
+type runningExecution struct {
+	id      string
+	jobName string
+	trigger domain.TriggerType
+	attempt int // Number of the first attempt run by this worker
+	cancel  context.CancelCauseFunc
+	logs    *logStream
 }
 
 // NewServer creates a new gRPC server for the worker.
-func NewServer(executors map[domain.ExecutorType]domain.TaskExecutor, locker domain.Locker, execRepo domain.ExecutionRepository, workerID string, logger *slog.Logger) *Server {
+func NewServer(executors map[domain.ExecutorType]domain.TaskExecutor, locker domain.Locker, execRepo domain.ExecutionRepository, retryQueue domain.RetryQueue, logStore domain.LogStore, outputLimit int, workerID string, logger *slog.Logger) *Server {
 	return &Server{
-		executors: executors,
-		locker:    locker,
-		execRepo:  execRepo,
-		workerID:  workerID,
-		logger:    logger.With("component", "grpc-server"),
-		tracer:    otel.Tracer("distributed-cron-worker"),
+		executors:   executors,
+		locker:      locker,
+		execRepo:    execRepo,
+		retryQueue:  retryQueue,
+		logStore:    logStore,
+		outputLimit: outputLimit,
+		workerID:    workerID,
+		logger:      logger.With("component", "grpc-server"),
+		tracer:      otel.Tracer("distributed-cron-worker"),
+		running:     make(map[string]*runningExecution),
 	}
 }
 
@@ -59,35 +84,194 @@ func (s *Server) ExecuteTask(ctx context.Context, req *pb.TaskRequest) (*pb.Task
 		return &pb.TaskResponse{ErrorMessage: err.Error()}, nil
 	}
 
-	executionID := uuid.NewString()
+	// Reject jobs this worker cannot run, so the master learns about it at dispatch time.
+	if checker, ok := s.executors[job.ExecutorType].(domain.PreflightChecker); ok {
+		if err := checker.Preflight(job); err != nil {
+			s.logger.Warn("rejected task", "job_name", job.Name, "error", err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "task rejected by preflight check")
+			return &pb.TaskResponse{ErrorMessage: err.Error()}, nil
+		}
+	}
+
+	// A retry dispatched from another worker continues the same execution.
+	executionID := req.ExecutionId
+	if executionID == "" {
+		executionID = uuid.NewString()
+	}
+	attempt := int(req.Attempt)
+	if attempt < 1 {
+		attempt = 1
+	}
+	trigger := domain.TriggerType(req.Trigger)
+	if trigger == "" {
+		trigger = domain.TriggerSchedule
+	}
+	span.SetAttributes(
+		attribute.String("execution.id", executionID),
+		attribute.String("execution.trigger", string(trigger)),
+		attribute.Int("execution.attempt", attempt),
+	)
+
+	// The full output goes to the log store; the record only keeps a truncated copy.
+	var store io.WriteCloser
+	if s.logStore != nil {
+		if store, err = s.logStore.Create(ctx, job.Name, executionID); err != nil {
+			s.logger.Error("failed to create execution log, full output will not be kept", "job_name", job.Name, "execution_id", executionID, "error", err)
+			span.RecordError(err)
+		}
+	}
+
+	// The execution is registered before the RPC returns, so the master can cancel it right away.
+	execCtx, cancel := context.WithCancelCause(context.Background())
+	execution := &runningExecution{
+		id:      executionID,
+		jobName: job.Name,
+		trigger: trigger,
+		attempt: attempt,
+		cancel:  cancel,
+		logs:    newLogStream(s.outputLimit, store),
+	}
+	s.trackExecution(executionID, execution)
 
-	go s.runJob(parentSpanContext, executionID, job)
+	go func() {
+		defer s.untrackExecution(executionID)
+		defer cancel(nil)
+		defer execution.logs.Close()
+		s.runJob(execCtx, parentSpanContext, job, execution)
+	}()
 
 	return &pb.TaskResponse{
 		ExecutionId: executionID,
 	}, nil
 }
 
+// Running returns the number of executions in progress on this worker.
+func (s *Server) Running() int {
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+	return len(s.running)
+}
+
+// CancelExecution is the RPC method called by the master to stop a running execution.
+func (s *Server) CancelExecution(ctx context.Context, req *pb.CancelExecutionRequest) (*pb.CancelExecutionResponse, error) {
+	_, span := s.tracer.Start(ctx, "worker.CancelExecution")
+	defer span.End()
+	span.SetAttributes(attribute.String("execution.id", req.ExecutionId))
+
+	s.runningMu.Lock()
+	execution, ok := s.running[req.ExecutionId]
+	s.runningMu.Unlock()
+	if !ok {
+		return &pb.CancelExecutionResponse{ErrorMessage: domain.ErrExecutionNotRunning.Error()}, nil
+	}
+
+	s.logger.Info("cancelling execution", "job_name", execution.jobName, "execution_id", req.ExecutionId)
+	execution.cancel(domain.ErrExecutionCancelled)
+	return &pb.CancelExecutionResponse{Cancelled: true}, nil
+}
+
+// StreamExecutionLogs is the RPC method called by the master to read the output of a running execution.
+func (s *Server) StreamExecutionLogs(req *pb.StreamExecutionLogsRequest, stream pb.Worker_StreamExecutionLogsServer) error {
+	ctx, span := s.tracer.Start(stream.Context(), "worker.StreamExecutionLogs")
+	defer span.End()
+	span.SetAttributes(attribute.String("execution.id", req.ExecutionId), attribute.Bool("follow", req.Follow))
+
+	s.runningMu.Lock()
+	execution, ok := s.running[req.ExecutionId]
+	s.runningMu.Unlock()
+	if !ok {
+		return status.Error(grpccodes.NotFound, domain.ErrExecutionNotRunning.Error())
+	}
+
+	offset := 0
+	for {
+		chunk, next, closed, changed := execution.logs.readFrom(offset)
+		if len(chunk) > 0 {
+			if err := stream.Send(&pb.LogChunk{Data: chunk}); err != nil {
+				return err
+			}
+		}
+		offset = next
+		if closed || !req.Follow {
+			return nil
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// GetExecutionOutput streams the full output of an execution from the log store.
+func (s *Server) GetExecutionOutput(req *pb.GetExecutionOutputRequest, stream pb.Worker_GetExecutionOutputServer) error {
+	ctx, span := s.tracer.Start(stream.Context(), "worker.GetExecutionOutput")
+	defer span.End()
+	span.SetAttributes(attribute.String("job.name", req.JobName), attribute.String("execution.id", req.ExecutionId))
+
+	if s.logStore == nil {
+		return status.Error(grpccodes.NotFound, domain.ErrLogNotFound.Error())
+	}
+	output, err := s.logStore.Open(ctx, req.JobName, req.ExecutionId)
+	if errors.Is(err, domain.ErrLogNotFound) {
+		return status.Error(grpccodes.NotFound, err.Error())
+	}
+	if err != nil {
+		span.RecordError(err)
+		return status.Error(grpccodes.Internal, err.Error())
+	}
+	defer output.Close()
+
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := output.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&pb.LogChunk{Data: buf[:n]}); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			span.RecordError(err)
+			return status.Error(grpccodes.Internal, err.Error())
+		}
+	}
+}
+
+func (s *Server) trackExecution(executionID string, execution *runningExecution) {
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+	s.running[executionID] = execution
+}
+
+func (s *Server) untrackExecution(executionID string) {
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+	delete(s.running, executionID)
+}
+
 // runJob handles the actual execution logic in the background.
-func (s *Server) runJob(parentSpanContext trace.SpanContext, executionID string, job *domain.Job) {
+// The execution stops early if execCtx is cancelled through CancelExecution.
+// Output is written to the execution's logs while the job runs and persisted in the final record.
+func (s *Server) runJob(execCtx context.Context, parentSpanContext trace.SpanContext, job *domain.Job, execution *runningExecution) {
 	ctx, span := s.tracer.Start(
-		context.Background(),
+		execCtx,
 		"worker.runJob",
 		trace.WithLinks(trace.Link{SpanContext: parentSpanContext}),
-		trace.WithAttributes(attribute.String("job.name", job.Name), attribute.String("execution.id", executionID)),
+		trace.WithAttributes(attribute.String("job.name", job.Name), attribute.String("execution.id", execution.id)),
 	)
 	defer span.End()
 
-	logger := s.logger.With("job_name", job.Name, "job_id", job.ID, "execution_id", executionID)
+	logger := s.logger.With("job_name", job.Name, "job_id", job.ID, "execution_id", execution.id)
+	logs := execution.logs
 
-	// Create the initial execution record
-	record := &domain.ExecutionRecord{
-		ID:        executionID,
-		JobName:   job.Name,
-		StartTime: time.Now(),
-		Status:    domain.ExecutionStatusRunning,
-		WorkerID:  s.workerID,
-	}
+	// Create the initial execution record, or continue the one left by the previous attempt.
+	record := s.loadOrCreateRecord(ctx, job, execution, logger)
+	logs.Write([]byte(record.Output))
 
 	// Save the initial "running" record
 	if err := s.execRepo.Save(ctx, record); err != nil {
@@ -102,11 +286,16 @@ func (s *Server) runJob(parentSpanContext trace.SpanContext, executionID string,
 		if r := recover(); r != nil {
 			record.Status = domain.ExecutionStatusFailed
 			record.Error = fmt.Sprintf("panic: %v", r)
-			span.RecordError(fmt.Errorf(record.Error))
+			span.RecordError(errors.New(record.Error))
 			span.SetStatus(codes.Error, "job execution panicked")
 			logger.Error("job execution panicked", "panic", r)
 		}
 
+		// A retry handed to the master was saved before it was enqueued; saving it again
+		// could only conflict with the next attempt.
+		if record.Status == domain.ExecutionStatusRetryPending {
+			return
+		}
 		// Save the final record state
 		if err := s.execRepo.Save(context.Background(), record); err != nil {
 			logger.Error("failed to save final execution record", "error", err)
@@ -116,8 +305,26 @@ func (s *Server) runJob(parentSpanContext trace.SpanContext, executionID string,
 
 	// The rest of the execution logic
 	var execErr error
+	var retryPending bool
 	defer func() {
-		if execErr != nil {
+		if retryPending {
+			record.Status = domain.ExecutionStatusRetryPending
+			record.Error = execErr.Error()
+			metrics.JobExecutionTotal.WithLabelValues(job.Name, string(domain.ExecutionStatusRetryPending)).Inc()
+			span.AddEvent("retry_handed_off")
+		} else if errors.Is(execErr, domain.ErrExecutionCancelled) {
+			record.Status = domain.ExecutionStatusCancelled
+			record.Error = execErr.Error()
+			metrics.JobExecutionTotal.WithLabelValues(job.Name, string(domain.ExecutionStatusCancelled)).Inc()
+			span.SetStatus(codes.Error, "job execution cancelled")
+			span.AddEvent("execution_cancelled")
+		} else if errors.Is(execErr, domain.ErrExecutionTimedOut) {
+			record.Status = domain.ExecutionStatusTimedOut
+			record.Error = execErr.Error()
+			metrics.JobExecutionTotal.WithLabelValues(job.Name, string(domain.ExecutionStatusTimedOut)).Inc()
+			span.SetStatus(codes.Error, "job execution timed out")
+			span.RecordError(execErr)
+		} else if execErr != nil {
 			record.Status = domain.ExecutionStatusFailed
 			record.Error = execErr.Error()
 			metrics.JobExecutionTotal.WithLabelValues(job.Name, "failed").Inc()
@@ -125,6 +332,7 @@ func (s *Server) runJob(parentSpanContext trace.SpanContext, executionID string,
 			span.RecordError(execErr)
 		} else {
 			record.Status = domain.ExecutionStatusSuccess
+			record.Error = ""
 			metrics.JobExecutionTotal.WithLabelValues(job.Name, "success").Inc()
 			span.SetStatus(codes.Ok, "job execution successful")
 		}
@@ -160,10 +368,34 @@ func (s *Server) runJob(parentSpanContext trace.SpanContext, executionID string,
 		return
 	}
 
-	// 3. Execute the task.
+	// 3. Execute the task, retrying according to the job's retry policy.
 	logger.Info("executing job")
-	output, execErr := executor.Execute(ctx, job)
-	record.Output = output
+	retryPending, execErr = s.runAttempts(ctx, job, executor, record, execution.attempt, logs, logger)
+	record.Output = logs.String()
+}
+
+// loadOrCreateRecord returns the record of the execution. Retries dispatched from
+// another worker continue the stored record so that all attempts end up in one place.
+func (s *Server) loadOrCreateRecord(ctx context.Context, job *domain.Job, execution *runningExecution, logger *slog.Logger) *domain.ExecutionRecord {
+	if execution.attempt > 1 {
+		record, err := s.execRepo.Get(ctx, job.Name, execution.id)
+		if err == nil {
+			record.Status = domain.ExecutionStatusRunning
+			record.WorkerID = s.workerID
+			record.EndTime = time.Time{}
+			return record
+		}
+		logger.Warn("failed to load record of previous attempts, starting a new one", "error", err)
+	}
+
+	return &domain.ExecutionRecord{
+		ID:        execution.id,
+		JobName:   job.Name,
+		StartTime: time.Now(),
+		Status:    domain.ExecutionStatusRunning,
+		WorkerID:  s.workerID,
+		Trigger:   execution.trigger,
+	}
 }
 
 // protoToDomain converts a protobuf TaskRequest to a domain.Job object.
@@ -176,6 +408,7 @@ func (s *Server) protoToDomain(req *pb.TaskRequest) (*domain.Job, error) {
 		ExecutorType:      domain.ExecutorType(req.ExecutorType),
 		ConcurrencyPolicy: domain.ConcurrencyPolicy(req.ConcurrencyPolicy),
 		CreatedAt:         req.CreatedAt.AsTime(),
+		TimeZone:          req.TimeZone,
 	}
 
 	switch job.ExecutorType {
@@ -184,28 +417,84 @@ func (s *Server) protoToDomain(req *pb.TaskRequest) (*domain.Job, error) {
 			return nil, fmt.Errorf("http_executor is nil for http job type")
 		}
 		job.Executor = domain.JobExecutor{
-			URL:    req.HttpExecutor.Url,
-			Method: req.HttpExecutor.Method,
+			URL:     req.HttpExecutor.Url,
+			Method:  req.HttpExecutor.Method,
+			Headers: req.HttpExecutor.Headers,
+			Body:    req.HttpExecutor.Body,
+		}
+		if auth := req.HttpExecutor.Auth; auth != nil {
+			job.Executor.Auth = &domain.HTTPAuth{
+				Type:     domain.HTTPAuthType(auth.Type),
+				Username: auth.Username,
+				Password: auth.Password,
+				Token:    auth.Token,
+			}
+		}
+		for _, code := range req.HttpExecutor.ExpectedStatus {
+			job.Executor.ExpectedStatus = append(job.Executor.ExpectedStatus, int(code))
+		}
+		if assertion := req.HttpExecutor.Assertion; assertion != nil {
+			job.Executor.Assertion = &domain.ResponseAssertion{
+				Type:       domain.AssertionType(assertion.Type),
+				Expression: assertion.Expression,
+				Value:      assertion.Value,
+			}
 		}
 	case domain.ExecutorTypeShell:
 		if req.ShellExecutor == nil {
 			return nil, fmt.Errorf("shell_executor is nil for shell job type")
 		}
 		job.Executor = domain.JobExecutor{
-			Command: req.ShellExecutor.Command,
+			Command:    req.ShellExecutor.Command,
+			Env:        req.ShellExecutor.Env,
+			WorkingDir: req.ShellExecutor.WorkingDir,
+			User:       req.ShellExecutor.User,
+			Group:      req.ShellExecutor.Group,
+		}
+		if req.ShellExecutor.KillGracePeriod != "" {
+			grace, err := time.ParseDuration(req.ShellExecutor.KillGracePeriod)
+			if err != nil {
+				return nil, fmt.Errorf("invalid kill grace period: %w", err)
+			}
+			job.Executor.KillGracePeriod = grace
+		}
+		if limits := req.ShellExecutor.Limits; limits != nil {
+			job.Executor.Limits = &domain.ResourceLimits{
+				CPU:      limits.Cpu,
+				MemoryMB: limits.MemoryMb,
+				Pids:     limits.Pids,
+			}
 		}
 	default:
 		return nil, fmt.Errorf("unknown executor type: %s", req.ExecutorType)
 	}
 
+	if req.Timeout != "" {
+		timeout, err := time.ParseDuration(req.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout duration: %w", err)
+		}
+		job.Timeout = timeout
+	}
+
 	if req.RetryPolicy != nil {
 		backoff, err := time.ParseDuration(req.RetryPolicy.Backoff)
 		if err != nil {
 			return nil, fmt.Errorf("invalid backoff duration: %w", err)
 		}
+		var maxBackoff time.Duration
+		if req.RetryPolicy.MaxBackoff != "" {
+			if maxBackoff, err = time.ParseDuration(req.RetryPolicy.MaxBackoff); err != nil {
+				return nil, fmt.Errorf("invalid max backoff duration: %w", err)
+			}
+		}
 		job.RetryPolicy = &domain.RetryPolicy{
-			MaxRetries: int(req.RetryPolicy.MaxRetries),
-			Backoff:    backoff,
+			MaxRetries:             int(req.RetryPolicy.MaxRetries),
+			Backoff:                backoff,
+			Multiplier:             req.RetryPolicy.Multiplier,
+			MaxBackoff:             maxBackoff,
+			Jitter:                 req.RetryPolicy.Jitter,
+			RetryOnDifferentWorker: req.RetryPolicy.RetryOnDifferentWorker,
 		}
 	}
 	return job, nil
@@ -34,6 +34,11 @@ type TaskRequest struct {
 	ConcurrencyPolicy string                 `protobuf:"bytes,7,opt,name=concurrency_policy,json=concurrencyPolicy,proto3" json:"concurrency_policy,omitempty"`
 	RetryPolicy       *RetryPolicy           `protobuf:"bytes,8,opt,name=retry_policy,json=retryPolicy,proto3" json:"retry_policy,omitempty"`
 	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	TimeZone          string                 `protobuf:"bytes,10,opt,name=time_zone,json=timeZone,proto3" json:"time_zone,omitempty"`          // IANA time zone name, e.g., "Asia/Shanghai"
+	Trigger           string                 `protobuf:"bytes,11,opt,name=trigger,proto3" json:"trigger,omitempty"`                            // What caused this dispatch: "schedule", "misfire" or "manual"
+	Timeout           string                 `protobuf:"bytes,12,opt,name=timeout,proto3" json:"timeout,omitempty"`                            // duration string, e.g., "5m"; empty means the worker default
+	ExecutionId       string                 `protobuf:"bytes,13,opt,name=execution_id,json=executionId,proto3" json:"execution_id,omitempty"` // Set when continuing an existing execution on another worker.
+	Attempt           int32                  `protobuf:"varint,14,opt,name=attempt,proto3" json:"attempt,omitempty"`                           // Number of the attempt to run; 0 means the first attempt.
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
@@ -131,12 +136,52 @@ func (x *TaskRequest) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *TaskRequest) GetTimeZone() string {
+	if x != nil {
+		return x.TimeZone
+	}
+	return ""
+}
+
+func (x *TaskRequest) GetTrigger() string {
+	if x != nil {
+		return x.Trigger
+	}
+	return ""
+}
+
+func (x *TaskRequest) GetTimeout() string {
+	if x != nil {
+		return x.Timeout
+	}
+	return ""
+}
+
+func (x *TaskRequest) GetExecutionId() string {
+	if x != nil {
+		return x.ExecutionId
+	}
+	return ""
+}
+
+func (x *TaskRequest) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
 type ExecutorHttp struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
-	Method        string                 `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Url            string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Method         string                 `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	Headers        map[string]string      `protobuf:"bytes,3,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Body           string                 `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	Auth           *HttpAuth              `protobuf:"bytes,5,opt,name=auth,proto3" json:"auth,omitempty"`
+	ExpectedStatus []int32                `protobuf:"varint,6,rep,packed,name=expected_status,json=expectedStatus,proto3" json:"expected_status,omitempty"` // Empty means any 2xx or 3xx status.
+	Assertion      *ResponseAssertion     `protobuf:"bytes,7,opt,name=assertion,proto3" json:"assertion,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ExecutorHttp) Reset() {
@@ -183,16 +228,185 @@ func (x *ExecutorHttp) GetMethod() string {
 	return ""
 }
 
-type ExecutorShell struct {
+func (x *ExecutorHttp) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *ExecutorHttp) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *ExecutorHttp) GetAuth() *HttpAuth {
+	if x != nil {
+		return x.Auth
+	}
+	return nil
+}
+
+func (x *ExecutorHttp) GetExpectedStatus() []int32 {
+	if x != nil {
+		return x.ExpectedStatus
+	}
+	return nil
+}
+
+func (x *ExecutorHttp) GetAssertion() *ResponseAssertion {
+	if x != nil {
+		return x.Assertion
+	}
+	return nil
+}
+
+type HttpAuth struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"` // "basic" or "bearer"
+	Username      string                 `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+	Password      string                 `protobuf:"bytes,3,opt,name=password,proto3" json:"password,omitempty"`
+	Token         string                 `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HttpAuth) Reset() {
+	*x = HttpAuth{}
+	mi := &file_worker_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HttpAuth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HttpAuth) ProtoMessage() {}
+
+func (x *HttpAuth) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HttpAuth.ProtoReflect.Descriptor instead.
+func (*HttpAuth) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *HttpAuth) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *HttpAuth) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *HttpAuth) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *HttpAuth) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type ResponseAssertion struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Command       string                 `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"` // "substring", "regex" or "jsonpath"
+	Expression    string                 `protobuf:"bytes,2,opt,name=expression,proto3" json:"expression,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"` // For jsonpath: the expected value; empty only requires the path to exist.
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *ResponseAssertion) Reset() {
+	*x = ResponseAssertion{}
+	mi := &file_worker_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseAssertion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseAssertion) ProtoMessage() {}
+
+func (x *ResponseAssertion) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseAssertion.ProtoReflect.Descriptor instead.
+func (*ResponseAssertion) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ResponseAssertion) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ResponseAssertion) GetExpression() string {
+	if x != nil {
+		return x.Expression
+	}
+	return ""
+}
+
+func (x *ResponseAssertion) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type ExecutorShell struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Command         string                 `protobuf:"bytes,1,opt,name=command,proto3" json:"command,omitempty"`
+	Env             map[string]string      `protobuf:"bytes,2,rep,name=env,proto3" json:"env,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	WorkingDir      string                 `protobuf:"bytes,3,opt,name=working_dir,json=workingDir,proto3" json:"working_dir,omitempty"`
+	User            string                 `protobuf:"bytes,4,opt,name=user,proto3" json:"user,omitempty"`                                                // User name or uid to run as; empty means the worker's user.
+	Group           string                 `protobuf:"bytes,5,opt,name=group,proto3" json:"group,omitempty"`                                              // Group name or gid to run as; empty means the user's primary group.
+	KillGracePeriod string                 `protobuf:"bytes,6,opt,name=kill_grace_period,json=killGracePeriod,proto3" json:"kill_grace_period,omitempty"` // duration string; empty means the worker default
+	Limits          *ResourceLimits        `protobuf:"bytes,7,opt,name=limits,proto3" json:"limits,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
 func (x *ExecutorShell) Reset() {
 	*x = ExecutorShell{}
-	mi := &file_worker_proto_msgTypes[2]
+	mi := &file_worker_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -204,7 +418,7 @@ func (x *ExecutorShell) String() string {
 func (*ExecutorShell) ProtoMessage() {}
 
 func (x *ExecutorShell) ProtoReflect() protoreflect.Message {
-	mi := &file_worker_proto_msgTypes[2]
+	mi := &file_worker_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -217,7 +431,7 @@ func (x *ExecutorShell) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExecutorShell.ProtoReflect.Descriptor instead.
 func (*ExecutorShell) Descriptor() ([]byte, []int) {
-	return file_worker_proto_rawDescGZIP(), []int{2}
+	return file_worker_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *ExecutorShell) GetCommand() string {
@@ -227,17 +441,123 @@ func (x *ExecutorShell) GetCommand() string {
 	return ""
 }
 
-type RetryPolicy struct {
+func (x *ExecutorShell) GetEnv() map[string]string {
+	if x != nil {
+		return x.Env
+	}
+	return nil
+}
+
+func (x *ExecutorShell) GetWorkingDir() string {
+	if x != nil {
+		return x.WorkingDir
+	}
+	return ""
+}
+
+func (x *ExecutorShell) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ExecutorShell) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
+func (x *ExecutorShell) GetKillGracePeriod() string {
+	if x != nil {
+		return x.KillGracePeriod
+	}
+	return ""
+}
+
+func (x *ExecutorShell) GetLimits() *ResourceLimits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+type ResourceLimits struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MaxRetries    int32                  `protobuf:"varint,1,opt,name=max_retries,json=maxRetries,proto3" json:"max_retries,omitempty"`
-	Backoff       string                 `protobuf:"bytes,2,opt,name=backoff,proto3" json:"backoff,omitempty"` // duration string, e.g., "30s"
+	Cpu           float64                `protobuf:"fixed64,1,opt,name=cpu,proto3" json:"cpu,omitempty"` // CPU cores
+	MemoryMb      int64                  `protobuf:"varint,2,opt,name=memory_mb,json=memoryMb,proto3" json:"memory_mb,omitempty"`
+	Pids          int64                  `protobuf:"varint,3,opt,name=pids,proto3" json:"pids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
+func (x *ResourceLimits) Reset() {
+	*x = ResourceLimits{}
+	mi := &file_worker_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResourceLimits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResourceLimits) ProtoMessage() {}
+
+func (x *ResourceLimits) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResourceLimits.ProtoReflect.Descriptor instead.
+func (*ResourceLimits) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ResourceLimits) GetCpu() float64 {
+	if x != nil {
+		return x.Cpu
+	}
+	return 0
+}
+
+func (x *ResourceLimits) GetMemoryMb() int64 {
+	if x != nil {
+		return x.MemoryMb
+	}
+	return 0
+}
+
+func (x *ResourceLimits) GetPids() int64 {
+	if x != nil {
+		return x.Pids
+	}
+	return 0
+}
+
+type RetryPolicy struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	MaxRetries             int32                  `protobuf:"varint,1,opt,name=max_retries,json=maxRetries,proto3" json:"max_retries,omitempty"`
+	Backoff                string                 `protobuf:"bytes,2,opt,name=backoff,proto3" json:"backoff,omitempty"` // duration string, e.g., "30s"
+	Multiplier             float64                `protobuf:"fixed64,3,opt,name=multiplier,proto3" json:"multiplier,omitempty"`
+	MaxBackoff             string                 `protobuf:"bytes,4,opt,name=max_backoff,json=maxBackoff,proto3" json:"max_backoff,omitempty"` // duration string; empty means unbounded
+	Jitter                 float64                `protobuf:"fixed64,5,opt,name=jitter,proto3" json:"jitter,omitempty"`
+	RetryOnDifferentWorker bool                   `protobuf:"varint,6,opt,name=retry_on_different_worker,json=retryOnDifferentWorker,proto3" json:"retry_on_different_worker,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
 func (x *RetryPolicy) Reset() {
 	*x = RetryPolicy{}
-	mi := &file_worker_proto_msgTypes[3]
+	mi := &file_worker_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -249,7 +569,7 @@ func (x *RetryPolicy) String() string {
 func (*RetryPolicy) ProtoMessage() {}
 
 func (x *RetryPolicy) ProtoReflect() protoreflect.Message {
-	mi := &file_worker_proto_msgTypes[3]
+	mi := &file_worker_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -262,7 +582,7 @@ func (x *RetryPolicy) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RetryPolicy.ProtoReflect.Descriptor instead.
 func (*RetryPolicy) Descriptor() ([]byte, []int) {
-	return file_worker_proto_rawDescGZIP(), []int{3}
+	return file_worker_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *RetryPolicy) GetMaxRetries() int32 {
@@ -279,6 +599,34 @@ func (x *RetryPolicy) GetBackoff() string {
 	return ""
 }
 
+func (x *RetryPolicy) GetMultiplier() float64 {
+	if x != nil {
+		return x.Multiplier
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetMaxBackoff() string {
+	if x != nil {
+		return x.MaxBackoff
+	}
+	return ""
+}
+
+func (x *RetryPolicy) GetJitter() float64 {
+	if x != nil {
+		return x.Jitter
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetRetryOnDifferentWorker() bool {
+	if x != nil {
+		return x.RetryOnDifferentWorker
+	}
+	return false
+}
+
 // The response message indicating if the task was accepted.
 type TaskResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -290,7 +638,7 @@ type TaskResponse struct {
 
 func (x *TaskResponse) Reset() {
 	*x = TaskResponse{}
-	mi := &file_worker_proto_msgTypes[4]
+	mi := &file_worker_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -302,7 +650,7 @@ func (x *TaskResponse) String() string {
 func (*TaskResponse) ProtoMessage() {}
 
 func (x *TaskResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_worker_proto_msgTypes[4]
+	mi := &file_worker_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -315,7 +663,7 @@ func (x *TaskResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TaskResponse.ProtoReflect.Descriptor instead.
 func (*TaskResponse) Descriptor() ([]byte, []int) {
-	return file_worker_proto_rawDescGZIP(), []int{4}
+	return file_worker_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *TaskResponse) GetExecutionId() string {
@@ -332,11 +680,255 @@ func (x *TaskResponse) GetErrorMessage() string {
 	return ""
 }
 
+type CancelExecutionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExecutionId   string                 `protobuf:"bytes,1,opt,name=execution_id,json=executionId,proto3" json:"execution_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelExecutionRequest) Reset() {
+	*x = CancelExecutionRequest{}
+	mi := &file_worker_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelExecutionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelExecutionRequest) ProtoMessage() {}
+
+func (x *CancelExecutionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelExecutionRequest.ProtoReflect.Descriptor instead.
+func (*CancelExecutionRequest) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CancelExecutionRequest) GetExecutionId() string {
+	if x != nil {
+		return x.ExecutionId
+	}
+	return ""
+}
+
+type CancelExecutionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cancelled     bool                   `protobuf:"varint,1,opt,name=cancelled,proto3" json:"cancelled,omitempty"` // False if the execution is not running on this worker.
+	ErrorMessage  string                 `protobuf:"bytes,2,opt,name=error_message,json=errorMessage,proto3" json:"error_message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelExecutionResponse) Reset() {
+	*x = CancelExecutionResponse{}
+	mi := &file_worker_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelExecutionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelExecutionResponse) ProtoMessage() {}
+
+func (x *CancelExecutionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelExecutionResponse.ProtoReflect.Descriptor instead.
+func (*CancelExecutionResponse) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CancelExecutionResponse) GetCancelled() bool {
+	if x != nil {
+		return x.Cancelled
+	}
+	return false
+}
+
+func (x *CancelExecutionResponse) GetErrorMessage() string {
+	if x != nil {
+		return x.ErrorMessage
+	}
+	return ""
+}
+
+type StreamExecutionLogsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExecutionId   string                 `protobuf:"bytes,1,opt,name=execution_id,json=executionId,proto3" json:"execution_id,omitempty"`
+	Follow        bool                   `protobuf:"varint,2,opt,name=follow,proto3" json:"follow,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamExecutionLogsRequest) Reset() {
+	*x = StreamExecutionLogsRequest{}
+	mi := &file_worker_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamExecutionLogsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamExecutionLogsRequest) ProtoMessage() {}
+
+func (x *StreamExecutionLogsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamExecutionLogsRequest.ProtoReflect.Descriptor instead.
+func (*StreamExecutionLogsRequest) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *StreamExecutionLogsRequest) GetExecutionId() string {
+	if x != nil {
+		return x.ExecutionId
+	}
+	return ""
+}
+
+func (x *StreamExecutionLogsRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+type GetExecutionOutputRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JobName       string                 `protobuf:"bytes,1,opt,name=job_name,json=jobName,proto3" json:"job_name,omitempty"`
+	ExecutionId   string                 `protobuf:"bytes,2,opt,name=execution_id,json=executionId,proto3" json:"execution_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetExecutionOutputRequest) Reset() {
+	*x = GetExecutionOutputRequest{}
+	mi := &file_worker_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetExecutionOutputRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetExecutionOutputRequest) ProtoMessage() {}
+
+func (x *GetExecutionOutputRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetExecutionOutputRequest.ProtoReflect.Descriptor instead.
+func (*GetExecutionOutputRequest) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetExecutionOutputRequest) GetJobName() string {
+	if x != nil {
+		return x.JobName
+	}
+	return ""
+}
+
+func (x *GetExecutionOutputRequest) GetExecutionId() string {
+	if x != nil {
+		return x.ExecutionId
+	}
+	return ""
+}
+
+type LogChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogChunk) Reset() {
+	*x = LogChunk{}
+	mi := &file_worker_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogChunk) ProtoMessage() {}
+
+func (x *LogChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_worker_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogChunk.ProtoReflect.Descriptor instead.
+func (*LogChunk) Descriptor() ([]byte, []int) {
+	return file_worker_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LogChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
 var File_worker_proto protoreflect.FileDescriptor
 
 const file_worker_proto_rawDesc = "" +
 	"\n" +
-	"\fworker.proto\x12\x05proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8b\x03\n" +
+	"\fworker.proto\x12\x05proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x99\x04\n" +
 	"\vTaskRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1b\n" +
@@ -347,21 +939,83 @@ const file_worker_proto_rawDesc = "" +
 	"\x12concurrency_policy\x18\a \x01(\tR\x11concurrencyPolicy\x125\n" +
 	"\fretry_policy\x18\b \x01(\v2\x12.proto.RetryPolicyR\vretryPolicy\x129\n" +
 	"\n" +
-	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"8\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x1b\n" +
+	"\ttime_zone\x18\n" +
+	" \x01(\tR\btimeZone\x12\x18\n" +
+	"\atrigger\x18\v \x01(\tR\atrigger\x12\x18\n" +
+	"\atimeout\x18\f \x01(\tR\atimeout\x12!\n" +
+	"\fexecution_id\x18\r \x01(\tR\vexecutionId\x12\x18\n" +
+	"\aattempt\x18\x0e \x01(\x05R\aattempt\"\xca\x02\n" +
 	"\fExecutorHttp\x12\x10\n" +
 	"\x03url\x18\x01 \x01(\tR\x03url\x12\x16\n" +
-	"\x06method\x18\x02 \x01(\tR\x06method\")\n" +
+	"\x06method\x18\x02 \x01(\tR\x06method\x12:\n" +
+	"\aheaders\x18\x03 \x03(\v2 .proto.ExecutorHttp.HeadersEntryR\aheaders\x12\x12\n" +
+	"\x04body\x18\x04 \x01(\tR\x04body\x12#\n" +
+	"\x04auth\x18\x05 \x01(\v2\x0f.proto.HttpAuthR\x04auth\x12'\n" +
+	"\x0fexpected_status\x18\x06 \x03(\x05R\x0eexpectedStatus\x126\n" +
+	"\tassertion\x18\a \x01(\v2\x18.proto.ResponseAssertionR\tassertion\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"l\n" +
+	"\bHttpAuth\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1a\n" +
+	"\busername\x18\x02 \x01(\tR\busername\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\tR\bpassword\x12\x14\n" +
+	"\x05token\x18\x04 \x01(\tR\x05token\"]\n" +
+	"\x11ResponseAssertion\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x1e\n" +
+	"\n" +
+	"expression\x18\x02 \x01(\tR\n" +
+	"expression\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\"\xb8\x02\n" +
 	"\rExecutorShell\x12\x18\n" +
-	"\acommand\x18\x01 \x01(\tR\acommand\"H\n" +
+	"\acommand\x18\x01 \x01(\tR\acommand\x12/\n" +
+	"\x03env\x18\x02 \x03(\v2\x1d.proto.ExecutorShell.EnvEntryR\x03env\x12\x1f\n" +
+	"\vworking_dir\x18\x03 \x01(\tR\n" +
+	"workingDir\x12\x12\n" +
+	"\x04user\x18\x04 \x01(\tR\x04user\x12\x14\n" +
+	"\x05group\x18\x05 \x01(\tR\x05group\x12*\n" +
+	"\x11kill_grace_period\x18\x06 \x01(\tR\x0fkillGracePeriod\x12-\n" +
+	"\x06limits\x18\a \x01(\v2\x15.proto.ResourceLimitsR\x06limits\x1a6\n" +
+	"\bEnvEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"S\n" +
+	"\x0eResourceLimits\x12\x10\n" +
+	"\x03cpu\x18\x01 \x01(\x01R\x03cpu\x12\x1b\n" +
+	"\tmemory_mb\x18\x02 \x01(\x03R\bmemoryMb\x12\x12\n" +
+	"\x04pids\x18\x03 \x01(\x03R\x04pids\"\xdc\x01\n" +
 	"\vRetryPolicy\x12\x1f\n" +
 	"\vmax_retries\x18\x01 \x01(\x05R\n" +
 	"maxRetries\x12\x18\n" +
-	"\abackoff\x18\x02 \x01(\tR\abackoff\"V\n" +
+	"\abackoff\x18\x02 \x01(\tR\abackoff\x12\x1e\n" +
+	"\n" +
+	"multiplier\x18\x03 \x01(\x01R\n" +
+	"multiplier\x12\x1f\n" +
+	"\vmax_backoff\x18\x04 \x01(\tR\n" +
+	"maxBackoff\x12\x16\n" +
+	"\x06jitter\x18\x05 \x01(\x01R\x06jitter\x129\n" +
+	"\x19retry_on_different_worker\x18\x06 \x01(\bR\x16retryOnDifferentWorker\"V\n" +
 	"\fTaskResponse\x12!\n" +
 	"\fexecution_id\x18\x01 \x01(\tR\vexecutionId\x12#\n" +
-	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage2@\n" +
+	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\";\n" +
+	"\x16CancelExecutionRequest\x12!\n" +
+	"\fexecution_id\x18\x01 \x01(\tR\vexecutionId\"\\\n" +
+	"\x17CancelExecutionResponse\x12\x1c\n" +
+	"\tcancelled\x18\x01 \x01(\bR\tcancelled\x12#\n" +
+	"\rerror_message\x18\x02 \x01(\tR\ferrorMessage\"W\n" +
+	"\x1aStreamExecutionLogsRequest\x12!\n" +
+	"\fexecution_id\x18\x01 \x01(\tR\vexecutionId\x12\x16\n" +
+	"\x06follow\x18\x02 \x01(\bR\x06follow\"Y\n" +
+	"\x19GetExecutionOutputRequest\x12\x19\n" +
+	"\bjob_name\x18\x01 \x01(\tR\ajobName\x12!\n" +
+	"\fexecution_id\x18\x02 \x01(\tR\vexecutionId\"\x1e\n" +
+	"\bLogChunk\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data2\xaa\x02\n" +
 	"\x06Worker\x126\n" +
-	"\vExecuteTask\x12\x12.proto.TaskRequest\x1a\x13.proto.TaskResponseB\tZ\a./protob\x06proto3"
+	"\vExecuteTask\x12\x12.proto.TaskRequest\x1a\x13.proto.TaskResponse\x12P\n" +
+	"\x0fCancelExecution\x12\x1d.proto.CancelExecutionRequest\x1a\x1e.proto.CancelExecutionResponse\x12K\n" +
+	"\x13StreamExecutionLogs\x12!.proto.StreamExecutionLogsRequest\x1a\x0f.proto.LogChunk0\x01\x12I\n" +
+	"\x12GetExecutionOutput\x12 .proto.GetExecutionOutputRequest\x1a\x0f.proto.LogChunk0\x01B\tZ\a./protob\x06proto3"
 
 var (
 	file_worker_proto_rawDescOnce sync.Once
@@ -375,27 +1029,48 @@ func file_worker_proto_rawDescGZIP() []byte {
 	return file_worker_proto_rawDescData
 }
 
-var file_worker_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_worker_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
 var file_worker_proto_goTypes = []any{
-	(*TaskRequest)(nil),           // 0: proto.TaskRequest
-	(*ExecutorHttp)(nil),          // 1: proto.ExecutorHttp
-	(*ExecutorShell)(nil),         // 2: proto.ExecutorShell
-	(*RetryPolicy)(nil),           // 3: proto.RetryPolicy
-	(*TaskResponse)(nil),          // 4: proto.TaskResponse
-	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
+	(*TaskRequest)(nil),                // 0: proto.TaskRequest
+	(*ExecutorHttp)(nil),               // 1: proto.ExecutorHttp
+	(*HttpAuth)(nil),                   // 2: proto.HttpAuth
+	(*ResponseAssertion)(nil),          // 3: proto.ResponseAssertion
+	(*ExecutorShell)(nil),              // 4: proto.ExecutorShell
+	(*ResourceLimits)(nil),             // 5: proto.ResourceLimits
+	(*RetryPolicy)(nil),                // 6: proto.RetryPolicy
+	(*TaskResponse)(nil),               // 7: proto.TaskResponse
+	(*CancelExecutionRequest)(nil),     // 8: proto.CancelExecutionRequest
+	(*CancelExecutionResponse)(nil),    // 9: proto.CancelExecutionResponse
+	(*StreamExecutionLogsRequest)(nil), // 10: proto.StreamExecutionLogsRequest
+	(*GetExecutionOutputRequest)(nil),  // 11: proto.GetExecutionOutputRequest
+	(*LogChunk)(nil),                   // 12: proto.LogChunk
+	nil,                                // 13: proto.ExecutorHttp.HeadersEntry
+	nil,                                // 14: proto.ExecutorShell.EnvEntry
+	(*timestamppb.Timestamp)(nil),      // 15: google.protobuf.Timestamp
 }
 var file_worker_proto_depIdxs = []int32{
-	1, // 0: proto.TaskRequest.http_executor:type_name -> proto.ExecutorHttp
-	2, // 1: proto.TaskRequest.shell_executor:type_name -> proto.ExecutorShell
-	3, // 2: proto.TaskRequest.retry_policy:type_name -> proto.RetryPolicy
-	5, // 3: proto.TaskRequest.created_at:type_name -> google.protobuf.Timestamp
-	0, // 4: proto.Worker.ExecuteTask:input_type -> proto.TaskRequest
-	4, // 5: proto.Worker.ExecuteTask:output_type -> proto.TaskResponse
-	5, // [5:6] is the sub-list for method output_type
-	4, // [4:5] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	1,  // 0: proto.TaskRequest.http_executor:type_name -> proto.ExecutorHttp
+	4,  // 1: proto.TaskRequest.shell_executor:type_name -> proto.ExecutorShell
+	6,  // 2: proto.TaskRequest.retry_policy:type_name -> proto.RetryPolicy
+	15, // 3: proto.TaskRequest.created_at:type_name -> google.protobuf.Timestamp
+	13, // 4: proto.ExecutorHttp.headers:type_name -> proto.ExecutorHttp.HeadersEntry
+	2,  // 5: proto.ExecutorHttp.auth:type_name -> proto.HttpAuth
+	3,  // 6: proto.ExecutorHttp.assertion:type_name -> proto.ResponseAssertion
+	14, // 7: proto.ExecutorShell.env:type_name -> proto.ExecutorShell.EnvEntry
+	5,  // 8: proto.ExecutorShell.limits:type_name -> proto.ResourceLimits
+	0,  // 9: proto.Worker.ExecuteTask:input_type -> proto.TaskRequest
+	8,  // 10: proto.Worker.CancelExecution:input_type -> proto.CancelExecutionRequest
+	10, // 11: proto.Worker.StreamExecutionLogs:input_type -> proto.StreamExecutionLogsRequest
+	11, // 12: proto.Worker.GetExecutionOutput:input_type -> proto.GetExecutionOutputRequest
+	7,  // 13: proto.Worker.ExecuteTask:output_type -> proto.TaskResponse
+	9,  // 14: proto.Worker.CancelExecution:output_type -> proto.CancelExecutionResponse
+	12, // 15: proto.Worker.StreamExecutionLogs:output_type -> proto.LogChunk
+	12, // 16: proto.Worker.GetExecutionOutput:output_type -> proto.LogChunk
+	13, // [13:17] is the sub-list for method output_type
+	9,  // [9:13] is the sub-list for method input_type
+	9,  // [9:9] is the sub-list for extension type_name
+	9,  // [9:9] is the sub-list for extension extendee
+	0,  // [0:9] is the sub-list for field type_name
 }
 
 func init() { file_worker_proto_init() }
@@ -409,7 +1084,7 @@ func file_worker_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_worker_proto_rawDesc), len(file_worker_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   5,
+			NumMessages:   15,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -19,7 +19,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Worker_ExecuteTask_FullMethodName = "/proto.Worker/ExecuteTask"
+	Worker_ExecuteTask_FullMethodName         = "/proto.Worker/ExecuteTask"
+	Worker_CancelExecution_FullMethodName     = "/proto.Worker/CancelExecution"
+	Worker_StreamExecutionLogs_FullMethodName = "/proto.Worker/StreamExecutionLogs"
+	Worker_GetExecutionOutput_FullMethodName  = "/proto.Worker/GetExecutionOutput"
 )
 
 // WorkerClient is the client API for Worker service.
@@ -30,6 +33,13 @@ const (
 type WorkerClient interface {
 	// Master calls this RPC to command a worker to execute a task.
 	ExecuteTask(ctx context.Context, in *TaskRequest, opts ...grpc.CallOption) (*TaskResponse, error)
+	// Master calls this RPC to stop an execution that is still running on this worker.
+	CancelExecution(ctx context.Context, in *CancelExecutionRequest, opts ...grpc.CallOption) (*CancelExecutionResponse, error)
+	// Master calls this RPC to read the output of an execution running on this worker.
+	// With follow set, the stream stays open and relays output until the execution ends.
+	StreamExecutionLogs(ctx context.Context, in *StreamExecutionLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogChunk], error)
+	// Master calls this RPC to read the full output of an execution from the worker's log store.
+	GetExecutionOutput(ctx context.Context, in *GetExecutionOutputRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogChunk], error)
 }
 
 type workerClient struct {
@@ -50,6 +60,54 @@ func (c *workerClient) ExecuteTask(ctx context.Context, in *TaskRequest, opts ..
 	return out, nil
 }
 
+func (c *workerClient) CancelExecution(ctx context.Context, in *CancelExecutionRequest, opts ...grpc.CallOption) (*CancelExecutionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelExecutionResponse)
+	err := c.cc.Invoke(ctx, Worker_CancelExecution_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *workerClient) StreamExecutionLogs(ctx context.Context, in *StreamExecutionLogsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Worker_ServiceDesc.Streams[0], Worker_StreamExecutionLogs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamExecutionLogsRequest, LogChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Worker_StreamExecutionLogsClient = grpc.ServerStreamingClient[LogChunk]
+
+func (c *workerClient) GetExecutionOutput(ctx context.Context, in *GetExecutionOutputRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Worker_ServiceDesc.Streams[1], Worker_GetExecutionOutput_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[GetExecutionOutputRequest, LogChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Worker_GetExecutionOutputClient = grpc.ServerStreamingClient[LogChunk]
+
 // WorkerServer is the server API for Worker service.
 // All implementations must embed UnimplementedWorkerServer
 // for forward compatibility.
@@ -58,6 +116,13 @@ func (c *workerClient) ExecuteTask(ctx context.Context, in *TaskRequest, opts ..
 type WorkerServer interface {
 	// Master calls this RPC to command a worker to execute a task.
 	ExecuteTask(context.Context, *TaskRequest) (*TaskResponse, error)
+	// Master calls this RPC to stop an execution that is still running on this worker.
+	CancelExecution(context.Context, *CancelExecutionRequest) (*CancelExecutionResponse, error)
+	// Master calls this RPC to read the output of an execution running on this worker.
+	// With follow set, the stream stays open and relays output until the execution ends.
+	StreamExecutionLogs(*StreamExecutionLogsRequest, grpc.ServerStreamingServer[LogChunk]) error
+	// Master calls this RPC to read the full output of an execution from the worker's log store.
+	GetExecutionOutput(*GetExecutionOutputRequest, grpc.ServerStreamingServer[LogChunk]) error
 	mustEmbedUnimplementedWorkerServer()
 }
 
@@ -71,6 +136,15 @@ type UnimplementedWorkerServer struct{}
 func (UnimplementedWorkerServer) ExecuteTask(context.Context, *TaskRequest) (*TaskResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ExecuteTask not implemented")
 }
+func (UnimplementedWorkerServer) CancelExecution(context.Context, *CancelExecutionRequest) (*CancelExecutionResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelExecution not implemented")
+}
+func (UnimplementedWorkerServer) StreamExecutionLogs(*StreamExecutionLogsRequest, grpc.ServerStreamingServer[LogChunk]) error {
+	return status.Error(codes.Unimplemented, "method StreamExecutionLogs not implemented")
+}
+func (UnimplementedWorkerServer) GetExecutionOutput(*GetExecutionOutputRequest, grpc.ServerStreamingServer[LogChunk]) error {
+	return status.Error(codes.Unimplemented, "method GetExecutionOutput not implemented")
+}
 func (UnimplementedWorkerServer) mustEmbedUnimplementedWorkerServer() {}
 func (UnimplementedWorkerServer) testEmbeddedByValue()                {}
 
@@ -110,6 +184,46 @@ func _Worker_ExecuteTask_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Worker_CancelExecution_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelExecutionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorkerServer).CancelExecution(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Worker_CancelExecution_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorkerServer).CancelExecution(ctx, req.(*CancelExecutionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Worker_StreamExecutionLogs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamExecutionLogsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkerServer).StreamExecutionLogs(m, &grpc.GenericServerStream[StreamExecutionLogsRequest, LogChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Worker_StreamExecutionLogsServer = grpc.ServerStreamingServer[LogChunk]
+
+func _Worker_GetExecutionOutput_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetExecutionOutputRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WorkerServer).GetExecutionOutput(m, &grpc.GenericServerStream[GetExecutionOutputRequest, LogChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Worker_GetExecutionOutputServer = grpc.ServerStreamingServer[LogChunk]
+
 // Worker_ServiceDesc is the grpc.ServiceDesc for Worker service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -121,7 +235,22 @@ var Worker_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ExecuteTask",
 			Handler:    _Worker_ExecuteTask_Handler,
 		},
+		{
+			MethodName: "CancelExecution",
+			Handler:    _Worker_CancelExecution_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamExecutionLogs",
+			Handler:       _Worker_StreamExecutionLogs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetExecutionOutput",
+			Handler:       _Worker_GetExecutionOutput_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "worker.proto",
 }